@@ -1,9 +1,11 @@
 package albums
 
 import (
+	"context"
+	"fmt"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
 	"golang.org/x/net/html"
-	"io/ioutil"
-	"net/http"
 	"strconv"
 	"strings"
 )
@@ -15,79 +17,85 @@ type Track struct {
 	Seconds int
 }
 
-func readTrack(n *html.Node) Track {
+// readTrack parses a tracklist row, tracks whose length upstream does not
+// know have an empty length cell and are left without duration.
+func readTrack(cells []*html.Node) (Track, error) {
 	var track Track
 
-	track.Name = strings.TrimSpace(n.FirstChild.Data)
-	stripedTime := strings.Split(n.NextSibling.NextSibling.FirstChild.Data, ":")
-	if len(stripedTime) == 2 {
-		track.Minutes, _ = strconv.Atoi(stripedTime[0])
-		track.Seconds, _ = strconv.Atoi(stripedTime[1])
-	} else {
-		track.Hours, _ = strconv.Atoi(stripedTime[0])
-		track.Minutes, _ = strconv.Atoi(stripedTime[1])
-		track.Seconds, _ = strconv.Atoi(stripedTime[2])
+	track.Name = types.Text(cells[1])
+	length := types.Text(cells[2])
+	if length == "" {
+		return track, nil
 	}
 
-	return track
-}
+	var parts []int
+	for _, part := range strings.Split(length, ":") {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return track, fmt.Errorf("Track '%s' length '%s' is not valid.", track.Name, length)
+		}
+		parts = append(parts, value)
+	}
+
+	switch len(parts) {
+	case 2:
+		track.Minutes, track.Seconds = parts[0], parts[1]
+	case 3:
+		track.Hours, track.Minutes, track.Seconds = parts[0], parts[1], parts[2]
+	default:
+		return track, fmt.Errorf("Track '%s' length '%s' is not valid.", track.Name, length)
+	}
 
-func getCoverURL(n *html.Node) string {
-	var cover string
+	return track, nil
+}
 
-	coverURL := n.FirstChild.NextSibling.Attr[3].Val
-	stripedCover := strings.Split(coverURL, "?")
+// getCoverURL returns the album cover without its version query, albums
+// without cover return an empty string.
+func getCoverURL(doc *html.Node) string {
+	albumImage := types.FindElement(doc, "div", types.WithClass("album_img"))
+	if albumImage == nil {
+		return ""
+	}
+	cover := types.FindElement(albumImage, "a", nil)
+	if cover == nil {
+		return ""
+	}
 
-	cover = stripedCover[0]
+	return strings.Split(types.Attribute(cover, "href"), "?")[0]
+}
 
-	return cover
+func GetAlbumInfo(client scraper.Client, albumURL string) ([]Track, string, error) {
+	return GetAlbumInfoWithContext(context.Background(), client, albumURL)
 }
 
-func GetAlbumInfo(client http.Client, albumURL string) ([]Track, string, error) {
+func GetAlbumInfoWithContext(ctx context.Context, client scraper.Client, albumURL string) ([]Track, string, error) {
 
 	var albumTracks []Track
 	var coverURL string
 
-	req, err := http.NewRequest(http.MethodGet, albumURL, nil)
-	if err != nil {
-		return albumTracks, coverURL, err
-	}
-
-	req.Header.Set("User-Agent", "https://github.com/a-castellano/metal-archives-wrapper")
-
-	res, getErr := client.Do(req)
+	body, getErr := client.GetWithContext(ctx, scraper.AlbumEndpoint, albumURL)
 	if getErr != nil {
-		return albumTracks, coverURL, err
-	}
-
-	body, readErr := ioutil.ReadAll(res.Body)
-	if readErr != nil {
-		return albumTracks, coverURL, err
+		return albumTracks, coverURL, getErr
 	}
 	stringBody := string(body)
 
 	doc, err := html.Parse(strings.NewReader(stringBody))
 	if err != nil {
-		return albumTracks, coverURL, err
+		return albumTracks, coverURL, &types.ParseError{What: "album page", Err: err}
 	}
-	var f func(*html.Node, *[]Track)
-	f = func(n *html.Node, albumTracks *[]Track) {
-		if n.Type == html.ElementNode && n.Data == "td" {
-			if len(n.Attr) == 1 && n.Attr[0].Val == "wrapWords" {
-				*albumTracks = append(*albumTracks, readTrack(n))
-			}
-		} else {
-			if n.Type == html.ElementNode && n.Data == "div" {
-				if len(n.Attr) == 1 && n.Attr[0].Val == "album_img" {
-					coverURL = getCoverURL(n)
-				}
-			}
+	for _, row := range types.FindElements(doc, "tr", nil) {
+		cells := types.ChildElements(row, "td")
+		if len(cells) < 3 || !types.HasClass(cells[1], "wrapWords") {
+			continue
 		}
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			f(c, albumTracks)
+		track, trackErr := readTrack(cells)
+		if trackErr != nil {
+			return albumTracks, coverURL, &types.ParseError{What: "album page", Err: trackErr}
 		}
+		albumTracks = append(albumTracks, track)
 	}
-	f(doc, &albumTracks)
+
+	coverURL = getCoverURL(doc)
 
 	return albumTracks, coverURL, nil
 }
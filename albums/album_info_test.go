@@ -4,7 +4,10 @@ package albums
 
 import (
 	"bytes"
+	"errors"
 	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
 	"io/ioutil"
 	"net/http"
 	"testing"
@@ -14,7 +17,7 @@ func TestGetTracks(t *testing.T) {
 
 	albumData := SearchAlbumData{Name: "Soma", URL: "https://www.metal-archives.com/albums/B%C3%B6lzer/Soma/447710", ID: 447710, Year: 2014, Artist: "Bölzer", ArtistID: 3540351548, ArtistURL: "https://www.metal-archives.com/bands/B%C3%B6lzer/3540351548", Type: commontypes.EP}
 
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 <!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd">
 <html xmlns="http://www.w3.org/1999/xhtml">
 <head>
@@ -579,7 +582,7 @@ Anointed at Temple of Sol.</p>
 </div>
 <script src="https://ajax.cloudflare.com/cdn-cgi/scripts/7089c43e/cloudflare-static/rocket-loader.min.js" data-cf-settings="609698a8fdb6bad6d2a2627d-|49" defer=""></script></body>
 </html>
-	`))}}}
+	`))}}})
 
 	tracks, cover, err := GetAlbumInfo(client, albumData.URL)
 
@@ -633,7 +636,7 @@ func TestLongerTracks(t *testing.T) {
 
 	albumData := SearchAlbumData{Name: "The Hunt", URL: "https://www.metal-archives.com/albums/Fauna/The_Hunt/189275", ID: 189275, Year: 2007, Artist: "Fauna", ArtistID: 121144, ArtistURL: "https://www.metal-archives.com/bands/Fauna/121144", Type: commontypes.FullLength}
 
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 <!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd">
 <html xmlns="http://www.w3.org/1999/xhtml">
 <head>
@@ -1096,7 +1099,7 @@ The re-released version is sequenced as seven tracks with the following titles a
 </div>
 <script src="https://ajax.cloudflare.com/cdn-cgi/scripts/7089c43e/cloudflare-static/rocket-loader.min.js" data-cf-settings="fa3d21b0fdc73efe12102b0f-|49" defer=""></script></body>
 </html>
-	`))}}}
+	`))}}})
 
 	tracks, cover, err := GetAlbumInfo(client, albumData.URL)
 
@@ -1128,3 +1131,42 @@ The re-released version is sequenced as seven tracks with the following titles a
 		t.Errorf("The Hunt by Fauna has cover located in 'https://www.metal-archives.com/images/1/8/9/2/189275.jpg', not %s'.", cover)
 	}
 }
+
+func TestGetAlbumInfoUnexpectedLayout(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+<html><body>
+<div class="album_img"></div>
+<table class="table_lyrics">
+<tr><td class="wrapWords"></td></tr>
+<tr><td width="20">1.</td><td class="wrapWords">Untimed</td><td align="right"></td></tr>
+</table>
+</body></html>`))}}})
+
+	tracks, cover, err := GetAlbumInfo(client, "https://www.metal-archives.com/albums/_/_/1")
+
+	if err != nil {
+		t.Fatalf("GetAlbumInfo shouldn't fail on unexpected layouts, error was '%s'.", err.Error())
+	}
+
+	if len(tracks) != 1 || tracks[0].Name != "Untimed" || tracks[0].Minutes != 0 {
+		t.Errorf("Only the complete track row should be read without length, found '%v'.", tracks)
+	}
+
+	if cover != "" {
+		t.Errorf("Albums without cover link should have no cover, not '%s'.", cover)
+	}
+}
+
+func TestGetAlbumInfoBrokenTrackLength(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+<html><body><table class="table_lyrics">
+<tr><td width="20">1.</td><td class="wrapWords">Steppes</td><td align="right">five minutes</td></tr>
+</table></body></html>`))}}})
+
+	_, _, err := GetAlbumInfo(client, "https://www.metal-archives.com/albums/_/_/1")
+
+	var parseErr *types.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("Broken track lengths should fail with a parse error, not '%v'.", err)
+	}
+}
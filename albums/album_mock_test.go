@@ -12,5 +12,9 @@ type RoundTripperMock struct {
 }
 
 func (rtm *RoundTripperMock) RoundTrip(*http.Request) (*http.Response, error) {
+	// Mocked responses without status are successful ones.
+	if rtm.Response != nil && rtm.Response.StatusCode == 0 {
+		rtm.Response.StatusCode = http.StatusOK
+	}
 	return rtm.Response, rtm.RespErr
 }
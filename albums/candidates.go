@@ -0,0 +1,77 @@
+package albums
+
+import (
+	"context"
+	"sort"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+)
+
+// AlbumCandidate is a release found searching an album name. Score is the
+// upstream relevance, Similarity how close the release name is to the
+// searched one from 0 to 1.
+type AlbumCandidate struct {
+	Album      SearchAlbumData
+	Score      float64
+	Similarity float64
+	Rank       int
+	Exact      bool
+}
+
+// Matched tells whether candidate name matches the searched name.
+func (candidate AlbumCandidate) Matched() bool {
+	return candidate.Rank != types.NoMatchRank
+}
+
+// GetAlbumCandidates returns every release found searching album, matching
+// releases first by rank and then by similarity, ties keep upstream order.
+func GetAlbumCandidates(ctx context.Context, client scraper.Client, album string) ([]AlbumCandidate, error) {
+	var candidates []AlbumCandidate
+
+	data, err := searchAlbumAjax(ctx, client, album)
+	if err != nil {
+		return candidates, err
+	}
+
+	for _, row := range data {
+		albumData, rowErr := readSearchAlbumRow(row)
+		if rowErr != nil {
+			return candidates, rowErr
+		}
+
+		candidate := AlbumCandidate{Album: albumData, Score: types.ReadScore(row[1])}
+		candidate.Rank = types.MatchRank(album, albumData.Name, client.MatchMode)
+		candidate.Exact = candidate.Rank == types.ExactMatchRank
+		candidate.Similarity = types.Similarity(album, albumData.Name)
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Rank != candidates[j].Rank {
+			return candidates[i].Rank < candidates[j].Rank
+		}
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+
+	return candidates, nil
+}
+
+// SelectAlbumMatches splits candidates as SearchAlbum does, the best
+// matching release and the other matching ones.
+func SelectAlbumMatches(candidates []AlbumCandidate) (SearchAlbumData, []SearchAlbumData, error) {
+	var albumData SearchAlbumData
+	var albumExtraData []SearchAlbumData
+
+	for _, candidate := range candidates {
+		if candidate.Matched() {
+			albumExtraData = append(albumExtraData, candidate.Album)
+		}
+	}
+
+	if len(albumExtraData) == 0 {
+		return albumData, nil, &types.NoMatchError{Message: "No album was found."}
+	}
+
+	return albumExtraData[0], albumExtraData[1:], nil
+}
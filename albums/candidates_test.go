@@ -0,0 +1,59 @@
+// +build integration_tests unit_tests
+
+package albums
+
+import (
+	"bytes"
+	"context"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestGetAlbumCandidates(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"error": "",
+	"iTotalRecords": 2,
+	"iTotalDisplayRecords": 2,
+	"sEcho": 0,
+	"aaData": [
+		[
+			"<a href=\"https://www.metal-archives.com/bands/Hyperborean/1111\" title=\"Hyperborean (US)\">Hyperborean</a>",
+			"<a href=\"https://www.metal-archives.com/albums/Hyperborean/Lateralus_Demo/1234\">Lateralus Demo</a> <!-- 0.9062499 -->" ,
+			"Demo"      ,
+			"2002 <!-- 2002-00-00 -->"     		],
+		[
+			"<a href=\"https://www.metal-archives.com/bands/Tool/2222\" title=\"Tool (US)\">Tool</a>",
+			"<a href=\"https://www.metal-archives.com/albums/Tool/Lateralus/4321\">Lateralus</a> <!-- 1.8124998 -->" ,
+			"Full-length"      ,
+			"May 15th, 2001 <!-- 2001-05-15 -->"     		]
+		]
+}
+	`))}}})
+
+	candidates, err := GetAlbumCandidates(context.Background(), client, "Lateralus")
+
+	if err != nil {
+		t.Fatalf("GetAlbumCandidates shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(candidates) != 2 {
+		t.Fatalf("GetAlbumCandidates should return every album found, it returned %d.", len(candidates))
+	}
+
+	if candidates[0].Album.ID != 4321 || !candidates[0].Exact || candidates[0].Score != 1.8124998 {
+		t.Errorf("Exact match 'Lateralus' should be the first candidate with its upstream score, not '%v'.", candidates[0])
+	}
+
+	if candidates[1].Matched() || candidates[1].Score != 0.9062499 || candidates[1].Similarity >= 1 {
+		t.Errorf("'Lateralus Demo' shouldn't match and should keep its upstream score, not '%v'.", candidates[1])
+	}
+
+	_, extraData, err := SelectAlbumMatches(candidates)
+
+	if err != nil || len(extraData) != 0 {
+		t.Errorf("SelectAlbumMatches should only return 'Lateralus'.")
+	}
+}
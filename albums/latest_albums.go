@@ -0,0 +1,76 @@
+package albums
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+)
+
+// AlbumChange is an album added by User at Date.
+type AlbumChange struct {
+	Album SearchAlbumData
+	Genre string
+	types.Change
+}
+
+var latestAlbumre = regexp.MustCompile(`<a href=['"]([^'"]*/albums/[^/]*/[^/]*/([0-9]+))['"][^>]*>([^<]+)</a>`)
+
+// readAlbumChangeRow parses day, band, album, type, genre, timestamp and user
+// rows. Splits list every band, only the first one is kept.
+func readAlbumChangeRow(row []string) (AlbumChange, error) {
+	var album AlbumChange
+
+	if len(row) < 7 {
+		return album, fmt.Errorf("Latest albums row has %d columns instead of 7.", len(row))
+	}
+
+	bandLink, bandErr := types.ReadBandLink(row[1])
+	albumMatch := latestAlbumre.FindStringSubmatch(row[2])
+	if bandErr != nil || albumMatch == nil {
+		return album, fmt.Errorf("Latest albums row has no album or artist link.")
+	}
+
+	album.Album.ArtistURL = bandLink.URL
+	album.Album.ArtistID, _ = strconv.Atoi(bandLink.ID)
+	album.Album.Artist = bandLink.Name
+	album.Album.URL = albumMatch[1]
+	album.Album.ID, _ = strconv.Atoi(albumMatch[2])
+	album.Album.Name = html.UnescapeString(albumMatch[3])
+	album.Album.Type = types.SelectRecordType(types.CellText(row[3]))
+	album.Genre = types.CellText(row[4])
+
+	change, err := types.ReadChange(row[5], row[6])
+	if err != nil {
+		return album, err
+	}
+	album.Change = change
+
+	return album, nil
+}
+
+// GetLatestAlbums returns every album added during month.
+func GetLatestAlbums(ctx context.Context, client scraper.Client, month time.Time) ([]AlbumChange, error) {
+	var albums []AlbumChange
+
+	url := fmt.Sprintf("/archives/ajax-album-list/selection/%s/by/created//json/1", types.ChangeMonth(month))
+	data, err := client.FetchAjaxPages(ctx, scraper.LatestEndpoint, url, 0, 0)
+	if err != nil {
+		return albums, err
+	}
+
+	for _, row := range data.Data {
+		album, rowErr := readAlbumChangeRow(row)
+		if rowErr != nil {
+			return albums, &types.ParseError{What: "latest albums", Err: rowErr}
+		}
+		albums = append(albums, album)
+	}
+
+	return albums, nil
+}
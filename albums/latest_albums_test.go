@@ -0,0 +1,91 @@
+// +build integration_tests unit_tests
+
+package albums
+
+import (
+	"context"
+	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetLatestAlbums(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Path
+		w.Write([]byte(`
+{
+	"error": "",
+	"iTotalRecords": 2,
+	"iTotalDisplayRecords": 2,
+	"sEcho": 0,
+	"aaData": [
+		[
+			"May 31",
+			"<a href=\"https://www.metal-archives.com/bands/Darkthrone/146\">Darkthrone</a>",
+			"<a href=\"https://www.metal-archives.com/albums/Darkthrone/It_Beckons_Us_All/1234\">It Beckons Us All</a>",
+			"Full-length",
+			"Black Metal, Heavy Metal",
+			"2024-05-31 21:47:23",
+			"<a href=\"https://www.metal-archives.com/users/Someone\" class=\"profileMenu\">Someone</a>"
+		],
+		[
+			"May 30",
+			"<a href=\"https://www.metal-archives.com/bands/Mayhem/67\">Mayhem</a> / <a href=\"https://www.metal-archives.com/bands/Burzum/88\">Burzum</a>",
+			"<a href=\"https://www.metal-archives.com/albums/Mayhem_-_Burzum/Split_%26_Split/4321\">Split &amp; Split</a>",
+			"Split",
+			"Black Metal",
+			"2024-05-30 10:00:00",
+			"Deleted user"
+		]
+	]
+}`))
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	albums, err := GetLatestAlbums(context.Background(), client, time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC))
+
+	if err != nil {
+		t.Fatalf("GetLatestAlbums shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if query != "/archives/ajax-album-list/selection/2024-05/by/created//json/1" {
+		t.Errorf("May 2024 additions should be requested, not '%s'.", query)
+	}
+
+	if len(albums) != 2 {
+		t.Fatalf("Every added album should be returned, returned %d.", len(albums))
+	}
+
+	if albums[0].Album.ID != 1234 || albums[0].Album.ArtistID != 146 || albums[0].Album.Type != commontypes.FullLength || albums[0].Genre != "Black Metal, Heavy Metal" {
+		t.Errorf("First album is wrong, found '%v'.", albums[0])
+	}
+
+	if !albums[0].Date.Equal(time.Date(2024, time.May, 31, 21, 47, 23, 0, time.UTC)) || albums[0].User != "Someone" {
+		t.Errorf("First album should be added by Someone on 2024-05-31 21:47:23, not by '%s' on '%s'.", albums[0].User, albums[0].Date)
+	}
+
+	if albums[1].Album.Name != "Split & Split" || albums[1].Album.Artist != "Mayhem" || albums[1].User != "Deleted user" {
+		t.Errorf("Split should keep its first band, found '%v'.", albums[1])
+	}
+}
+
+func TestGetLatestAlbumsBrokenRow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"iTotalRecords": 1, "iTotalDisplayRecords": 1, "aaData": [["May 31", "Darkthrone", "It Beckons Us All", "Full-length", "Black Metal", "2024-05-31 21:47:23", "Someone"]]}`))
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	if _, err := GetLatestAlbums(context.Background(), client, time.Now()); err == nil {
+		t.Errorf("Rows without links should fail.")
+	}
+}
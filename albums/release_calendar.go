@@ -0,0 +1,127 @@
+package albums
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	calendarProductID = "-//a-castellano//music-manager-metal-archives-wrapper//EN"
+	calendarDate      = "20060102"
+	calendarTimestamp = "20060102T150405Z"
+	// calendarLineLength is the maximum line length in octets before folding.
+	calendarLineLength = 75
+)
+
+var calendarEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// watchedRelease tells whether any band of release is among watched IDs.
+func watchedRelease(release UpcomingRelease, watched map[string]bool) bool {
+	for _, artist := range release.Artists {
+		if watched[strconv.Itoa(artist.ID)] {
+			return true
+		}
+	}
+	return false
+}
+
+// approximateDate returns the known part of release dates without day, such
+// as "June 2024" or "2024", and an empty string for exact dates.
+func approximateDate(release UpcomingRelease) string {
+	switch release.Precision {
+	case MonthPrecision:
+		return release.Date.Format("January 2006")
+	case YearPrecision:
+		return release.Date.Format("2006")
+	default:
+		return ""
+	}
+}
+
+// foldCalendarLine splits line into CRLF terminated lines of at most 75
+// octets, continuation lines start with a space. UTF-8 sequences are kept.
+func foldCalendarLine(line string) string {
+	var folded strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > calendarLineLength {
+			folded.WriteString("\r\n ")
+			length = 1
+		}
+		folded.WriteRune(r)
+		length += size
+	}
+	folded.WriteString("\r\n")
+	return folded.String()
+}
+
+// WriteCalendar writes releases as an iCalendar file of all day events. When
+// watched artist IDs are given only releases from those bands are written.
+// Releases whose day upstream does not know yet are written on the first
+// day of their month or year, their summary and description tell the date
+// is approximate.
+func WriteCalendar(w io.Writer, releases []UpcomingRelease, watched []string) error {
+	watchedIDs := make(map[string]bool)
+	for _, id := range watched {
+		watchedIDs[id] = true
+	}
+
+	writer := bufio.NewWriter(w)
+	line := func(format string, args ...interface{}) {
+		writer.WriteString(foldCalendarLine(fmt.Sprintf(format, args...)))
+	}
+
+	now := time.Now().UTC()
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:%s", calendarProductID)
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:Upcoming releases")
+
+	for _, release := range releases {
+		if len(watchedIDs) > 0 && !watchedRelease(release, watchedIDs) {
+			continue
+		}
+
+		var bands []string
+		for _, artist := range release.Artists {
+			bands = append(bands, artist.Name)
+		}
+		stamp := release.Added
+		if stamp.IsZero() {
+			stamp = now
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:%d@metal-archives.com", release.Album.ID)
+		line("DTSTAMP:%s", stamp.UTC().Format(calendarTimestamp))
+		line("DTSTART;VALUE=DATE:%s", release.Date.Format(calendarDate))
+		line("DTEND;VALUE=DATE:%s", release.Date.AddDate(0, 0, 1).Format(calendarDate))
+		summary := strings.Join(bands, " / ") + " - " + release.Album.Name
+		var description []string
+		if release.Genre != "" {
+			description = append(description, release.Genre)
+		}
+		if approximate := approximateDate(release); approximate != "" {
+			summary += " (" + approximate + ")"
+			description = append(description, "Release date is approximate, upstream only announces "+approximate+".")
+		}
+
+		line("SUMMARY:%s", calendarEscaper.Replace(summary))
+		if len(description) > 0 {
+			line("DESCRIPTION:%s", calendarEscaper.Replace(strings.Join(description, "\n")))
+		}
+		if release.Album.URL != "" {
+			line("URL:%s", release.Album.URL)
+		}
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return writer.Flush()
+}
@@ -1,16 +1,15 @@
 package albums
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
 	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
 	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
-	"io/ioutil"
-	"net/http"
+	"html"
+	"net/url"
 	"regexp"
 	"strconv"
-	"strings"
 )
 
 type SearchAlbumData struct {
@@ -26,104 +25,68 @@ type SearchAlbumData struct {
 	Tracks    []Track
 }
 
-func searchAlbumAjax(client http.Client, album string) ([][]string, error) {
+func searchAlbumAjax(ctx context.Context, client scraper.Client, album string) ([][]string, error) {
 
 	var searchAlbumData [][]string
-	albumString := strings.Replace(album, " ", "+", -1)
-	url := fmt.Sprintf("https://www.metal-archives.com/search/ajax-album-search/?field=title&query=%s", albumString)
+	searchURL := fmt.Sprintf("/search/ajax-album-search/?field=title&query=%s", url.QueryEscape(album))
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	searchAlbum, err := client.FetchAjaxPages(ctx, scraper.SearchEndpoint, searchURL, 0, client.MaxResults)
 	if err != nil {
 		return searchAlbumData, err
 	}
+	searchAlbumData = searchAlbum.Data
+	return searchAlbumData, nil
+}
 
-	req.Header.Set("User-Agent", "https://github.com/a-castellano/metal-archives-wrapper")
+func readSearchAlbumRow(row []string) (SearchAlbumData, error) {
+	albumDatare := regexp.MustCompile(`(?m)<a href="([^"]*)">([^<]*)</a> <!-- [0-9]*.[0-9]* -->$`)
+	yearre := regexp.MustCompile(`(?m)([1|2][0-9]{3})`)
+	albumIDre := regexp.MustCompile(`(?m)[^/]*//[^/]*/[^/]*/[^/]*[^/]*/[^/]*/([0-9]*)`)
 
-	res, getErr := client.Do(req)
-	if getErr != nil {
-		return searchAlbumData, getErr
+	var albumData SearchAlbumData
+
+	if len(row) < 4 {
+		return albumData, &types.ParseError{What: "album search", Err: fmt.Errorf("Album search row has %d columns instead of 4.", len(row))}
 	}
 
-	body, readErr := ioutil.ReadAll(res.Body)
-	if readErr != nil {
-		return searchAlbumData, readErr
+	albumMatch := albumDatare.FindAllStringSubmatch(row[1], -1)
+	artistLink, artistErr := types.ReadBandLink(row[0])
+	if albumMatch == nil || artistErr != nil {
+		return albumData, &types.ParseError{What: "album search", Err: fmt.Errorf("Album search row has no album or artist link.")}
 	}
 
-	searchAlbum := types.SearchAjaxData{}
-	jsonErr := json.Unmarshal(body, &searchAlbum)
-	if jsonErr != nil {
-		return searchAlbumData, jsonErr
+	albumData.URL = albumMatch[0][1]
+	albumData.Name = html.UnescapeString(albumMatch[0][2])
+
+	albumIDMatch := albumIDre.FindAllStringSubmatch(albumData.URL, -1)
+	if albumIDMatch == nil {
+		return albumData, &types.ParseError{What: "album search", Err: fmt.Errorf("Album URL '%s' has no ID.", albumData.URL)}
 	}
-	searchAlbumData = searchAlbum.Data
-	return searchAlbumData, nil
-}
+	albumData.ID, _ = strconv.Atoi(albumIDMatch[0][1])
 
-func SearchAlbum(client http.Client, album string) (SearchAlbumData, []SearchAlbumData, error) {
+	albumData.ArtistID, _ = strconv.Atoi(artistLink.ID)
+	albumData.Artist = artistLink.Name
+	albumData.ArtistURL = artistLink.URL
 
-	var albumData SearchAlbumData
-	var albumExtraData []SearchAlbumData
+	albumData.Type = types.SelectRecordType(row[2])
+	yearMatch := yearre.FindAllStringSubmatch(row[3], 1)
+	if yearMatch != nil {
+		albumData.Year, _ = strconv.Atoi(yearMatch[0][0])
+	}
 
-	artistDatare := regexp.MustCompile(`(?m)<[^/]*//[^/]*/[^/]*/[^/]*/([0-9]*)[^>]*>([^<]*)</a>$`)
-	albumDatare := regexp.MustCompile(`(?m)<a href="([^"]*)">([^<]*)</a> <!-- [0-9]*.[0-9]* -->$`)
-	artistURLre := regexp.MustCompile(`(?m)<a href="([^"]*)".*$`)
-	yearre := regexp.MustCompile(`(?m)([1|2][0-9]{3})`)
-	albumIDre := regexp.MustCompile(`(?m)[^/]*//[^/]*/[^/]*/[^/]*[^/]*/[^/]*/([0-9]*)`)
+	return albumData, nil
+}
 
-	data, err := searchAlbumAjax(client, album)
+func SearchAlbum(client scraper.Client, album string) (SearchAlbumData, []SearchAlbumData, error) {
+	return SearchAlbumWithContext(context.Background(), client, album)
+}
 
-	var found bool = false
+func SearchAlbumWithContext(ctx context.Context, client scraper.Client, album string) (SearchAlbumData, []SearchAlbumData, error) {
 
+	candidates, err := GetAlbumCandidates(ctx, client, album)
 	if err != nil {
-		return albumData, albumExtraData, err
-	} else {
-		for _, foundAlbumData := range data {
-			albumMatch := albumDatare.FindAllStringSubmatch(foundAlbumData[1], -1)
-			if strings.ToLower(albumMatch[0][2]) == strings.ToLower(album) {
-				if found == false {
-					found = true
-					albumData.URL = albumMatch[0][1]
-					albumData.Name = albumMatch[0][2]
-
-					albumIDMatch := albumIDre.FindAllStringSubmatch(albumData.URL, -1)
-
-					albumData.ID, _ = strconv.Atoi(albumIDMatch[0][1])
-
-					artistMatch := artistDatare.FindAllStringSubmatch(foundAlbumData[0], -1)
-					albumData.ArtistID, _ = strconv.Atoi(artistMatch[0][1])
-					albumData.Artist = artistMatch[0][2]
-					artistURLMatch := artistURLre.FindAllStringSubmatch(foundAlbumData[0], -1)
-					albumData.ArtistURL = artistURLMatch[0][1]
-
-					albumData.Type = types.SelectRecordType(foundAlbumData[2])
-					yearMatch := yearre.FindAllStringSubmatch(foundAlbumData[3], 1)
-					albumData.Year, _ = strconv.Atoi(yearMatch[0][0])
-				} else {
-					var extraAlbumData SearchAlbumData
-					extraAlbumData.URL = albumMatch[0][1]
-					extraAlbumData.Name = albumMatch[0][2]
-
-					albumIDMatch := albumIDre.FindAllStringSubmatch(extraAlbumData.URL, -1)
-					extraAlbumData.ID, _ = strconv.Atoi(albumIDMatch[0][1])
-
-					artistMatch := artistDatare.FindAllStringSubmatch(foundAlbumData[0], -1)
-					extraAlbumData.ArtistID, _ = strconv.Atoi(artistMatch[0][1])
-					extraAlbumData.Artist = artistMatch[0][2]
-					artistURLMatch := artistURLre.FindAllStringSubmatch(foundAlbumData[0], -1)
-					extraAlbumData.ArtistURL = artistURLMatch[0][1]
-
-					extraAlbumData.Type = types.SelectRecordType(foundAlbumData[2])
-					yearMatch := yearre.FindAllStringSubmatch(foundAlbumData[3], 1)
-					extraAlbumData.Year, _ = strconv.Atoi(yearMatch[0][0])
-
-					albumExtraData = append(albumExtraData, extraAlbumData)
-				}
-			}
-		}
-	}
-
-	if !found {
-		return albumData, albumExtraData, errors.New("No album was found.")
+		return SearchAlbumData{}, nil, err
 	}
 
-	return albumData, albumExtraData, nil
+	return SelectAlbumMatches(candidates)
 }
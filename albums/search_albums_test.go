@@ -4,14 +4,18 @@ package albums
 
 import (
 	"bytes"
+	"context"
 	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
 func TestSearchAlbumAjaxNoAlbum(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 0,
@@ -20,9 +24,9 @@ func TestSearchAlbumAjaxNoAlbum(t *testing.T) {
 	"aaData": [
 		]
 }
-	`))}}}
+	`))}}})
 
-	data, err := searchAlbumAjax(client, "AnyAlbum")
+	data, err := searchAlbumAjax(context.Background(), client, "AnyAlbum")
 
 	if err != nil {
 		t.Errorf("TestSearchAlbumAjaxNoAlbum shouldn't fail.")
@@ -35,7 +39,7 @@ func TestSearchAlbumAjaxNoAlbum(t *testing.T) {
 }
 
 func TestSearchAlbumAjaxBrokenJson(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 0,
@@ -43,9 +47,9 @@ func TestSearchAlbumAjaxBrokenJson(t *testing.T) {
 	"sEcho": 0,
 	"aaData": [
 }
-	`))}}}
+	`))}}})
 
-	data, err := searchAlbumAjax(client, "AnyAlbum")
+	data, err := searchAlbumAjax(context.Background(), client, "AnyAlbum")
 
 	if err == nil {
 		t.Errorf("TestSearchAlbumAjaxBrokenJson should fail.")
@@ -58,7 +62,7 @@ func TestSearchAlbumAjaxBrokenJson(t *testing.T) {
 }
 
 func TestSearchAlbumOneAlbum(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 1,
@@ -72,9 +76,9 @@ func TestSearchAlbumOneAlbum(t *testing.T) {
 			"August 16th, 1990 <!-- 1990-08-16 -->"     		]
 		]
 }
-	`))}}}
+	`))}}})
 
-	data, err := searchAlbumAjax(client, "AnyAlbum")
+	data, err := searchAlbumAjax(context.Background(), client, "AnyAlbum")
 
 	if err != nil {
 		t.Errorf("TestSearchAlbumOneAlbum shouldn't fail.")
@@ -87,7 +91,7 @@ func TestSearchAlbumOneAlbum(t *testing.T) {
 }
 
 func TestSearchAlbumMoreThanOneAlbum(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 4,
@@ -119,9 +123,9 @@ func TestSearchAlbumMoreThanOneAlbum(t *testing.T) {
 			"November 3rd, 2017 <!-- 2017-11-03 -->"     		]
 				]
 }
-	`))}}}
+	`))}}})
 
-	data, err := searchAlbumAjax(client, "AnyAlbum")
+	data, err := searchAlbumAjax(context.Background(), client, "AnyAlbum")
 
 	if err != nil {
 		t.Errorf("TestSearchAlbumMoreThanOneAlbum shouldn't fail.")
@@ -134,7 +138,7 @@ func TestSearchAlbumMoreThanOneAlbum(t *testing.T) {
 }
 
 func TestSearchAlbumErrored(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 0,
@@ -142,7 +146,7 @@ func TestSearchAlbumErrored(t *testing.T) {
 	"sEcho": 0,
 	"aaData": [
 }
-	`))}}}
+	`))}}})
 
 	data, extraData, err := SearchAlbum(client, "AnyAlbum")
 
@@ -160,7 +164,7 @@ func TestSearchAlbumErrored(t *testing.T) {
 }
 
 func TestSearchAlbumNotFound(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 1,
@@ -174,7 +178,7 @@ func TestSearchAlbumNotFound(t *testing.T) {
 			"August 16th, 1990 <!-- 1990-08-16 -->"     		]
 		]
 }
-	`))}}}
+	`))}}})
 
 	data, extraData, err := SearchAlbum(client, "AnyAlbum")
 
@@ -196,7 +200,7 @@ func TestSearchAlbumNotFound(t *testing.T) {
 }
 
 func TestSearchAlbumOneAlbumFound(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 1,
@@ -210,7 +214,7 @@ func TestSearchAlbumOneAlbumFound(t *testing.T) {
 			"August 16th, 1990 <!-- 1990-08-16 -->"     		]
 		]
 }
-	`))}}}
+	`))}}})
 
 	data, extraData, err := SearchAlbum(client, "Eaten Back to Life")
 
@@ -257,7 +261,7 @@ func TestSearchAlbumOneAlbumFound(t *testing.T) {
 }
 
 func TestSearchAlbumMoreThanOneAlbumFound(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 4,
@@ -289,7 +293,7 @@ func TestSearchAlbumMoreThanOneAlbumFound(t *testing.T) {
 			"November 3rd, 2017 <!-- 2017-11-03 -->"     		]
 				]
 }
-	`))}}}
+	`))}}})
 
 	data, extraData, err := SearchAlbum(client, "Agent Orange")
 
@@ -401,3 +405,89 @@ func TestSearchAlbumMoreThanOneAlbumFound(t *testing.T) {
 	}
 
 }
+
+func TestSearchAlbumNormalizedMatch(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"error": "",
+	"iTotalRecords": 2,
+	"iTotalDisplayRecords": 2,
+	"sEcho": 0,
+	"aaData": [
+		[
+			"<a href=\"https://www.metal-archives.com/bands/Hyperborean/1111\" title=\"Hyperborean (US)\">Hyperborean</a>",
+			"<a href=\"https://www.metal-archives.com/albums/Hyperborean/Aenima/1234\">Aenima</a> <!-- 1.8124998 -->" ,
+			"Demo"      ,
+			"2002 <!-- 2002-00-00 -->"     		],
+		[
+			"<a href=\"https://www.metal-archives.com/bands/Tool/2222\" title=\"Tool (US)\">Tool</a>",
+			"<a href=\"https://www.metal-archives.com/albums/Tool/%C3%86nima/4321\">Ænima</a> <!-- 1.8124998 -->" ,
+			"Full-length"      ,
+			"September 17th, 1996 <!-- 1996-09-17 -->"     		]
+		]
+}
+	`))}}})
+
+	data, extraData, err := SearchAlbum(client, "Ænima")
+
+	if err != nil {
+		t.Fatalf("SearchAlbum shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if data.ID != 4321 {
+		t.Errorf("Exact byte match should be ranked first, found album ID is %d.", data.ID)
+	}
+
+	if len(extraData) != 1 || extraData[0].ID != 1234 {
+		t.Errorf("Normalized match should be returned as extra data.")
+	}
+
+	client.MatchMode = types.CaseInsensitiveMatch
+	client.HTTPClient.Transport = &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"aaData": [
+		[
+			"<a href=\"https://www.metal-archives.com/bands/Tool/2222\" title=\"Tool (US)\">Tool</a>",
+			"<a href=\"https://www.metal-archives.com/albums/Tool/%C3%86nima/4321\">Ænima</a> <!-- 1.8124998 -->" ,
+			"Full-length"      ,
+			"September 17th, 1996 <!-- 1996-09-17 -->"     		]
+		]
+}
+	`))}}
+
+	_, _, err = SearchAlbum(client, "Aenima")
+
+	if err == nil {
+		t.Errorf("SearchAlbum shouldn't find 'Ænima' searching 'Aenima' when match mode is case insensitive.")
+	}
+}
+
+func TestSearchAlbumAmpersand(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		w.Write([]byte(`{"iTotalRecords": 1, "iTotalDisplayRecords": 1, "aaData": [["<a href=\"https://www.metal-archives.com/bands/Kiss_%26_Tell/2222\" title=\"Kiss &amp; Tell (US)\">Kiss &amp; Tell</a>", "<a href=\"https://www.metal-archives.com/albums/Kiss_%26_Tell/Blood_%26_Iron_%231/4321\">Blood &amp; Iron #1</a> <!-- 1.8124998 -->", "Demo", "2002 <!-- 2002-00-00 -->"]]}`))
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	data, _, err := SearchAlbum(client, "Blood & Iron #1")
+
+	if err != nil {
+		t.Fatalf("SearchAlbum shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if query != "Blood & Iron #1" {
+		t.Errorf("Searched title should reach upstream whole, not as '%s'.", query)
+	}
+
+	if data.Name != "Blood & Iron #1" || data.Artist != "Kiss & Tell" || data.ID != 4321 {
+		t.Errorf("Found album and band names should be unescaped, found '%s' by '%s'.", data.Name, data.Artist)
+	}
+
+	if _, _, err := SearchAlbum(client, "Blood and Iron 1"); err != nil {
+		t.Errorf("'Blood and Iron 1' should match 'Blood & Iron #1', error was '%s'.", err.Error())
+	}
+}
@@ -0,0 +1,141 @@
+package albums
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+)
+
+// DatePrecision tells which parts of a release date upstream knows.
+type DatePrecision int
+
+const (
+	DayPrecision DatePrecision = iota
+	MonthPrecision
+	YearPrecision
+)
+
+// ReleaseArtist is one of the bands of an upcoming release.
+type ReleaseArtist struct {
+	Name string
+	URL  string
+	ID   int
+}
+
+// UpcomingRelease is a release listed in the upcoming releases calendar.
+// Album artist fields hold its first band, splits list every band in
+// Artists. Date is set to the first day of its month or year when upstream
+// does not know the day, see Precision.
+type UpcomingRelease struct {
+	Album     SearchAlbumData
+	Artists   []ReleaseArtist
+	Genre     string
+	Date      time.Time
+	Precision DatePrecision
+	Added     time.Time
+}
+
+var (
+	releaseDatere  = regexp.MustCompile(`^([A-Za-z]+) ([0-9]{1,2})(?:st|nd|rd|th)?, ([0-9]{4})$`)
+	releaseMonthre = regexp.MustCompile(`^([A-Za-z]+),? ([0-9]{4})$`)
+	releaseYearre  = regexp.MustCompile(`^([0-9]{4})$`)
+)
+
+// ParseReleaseDate reads upstream release dates such as "June 7th, 2024",
+// "June 2024" or "2024".
+func ParseReleaseDate(value string) (time.Time, DatePrecision, error) {
+	value = types.CellText(value)
+
+	if match := releaseDatere.FindStringSubmatch(value); match != nil {
+		date, err := time.Parse("January 2 2006", fmt.Sprintf("%s %s %s", match[1], match[2], match[3]))
+		if err == nil {
+			return date, DayPrecision, nil
+		}
+	}
+	if match := releaseMonthre.FindStringSubmatch(value); match != nil {
+		date, err := time.Parse("January 2006", fmt.Sprintf("%s %s", match[1], match[2]))
+		if err == nil {
+			return date, MonthPrecision, nil
+		}
+	}
+	if match := releaseYearre.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), YearPrecision, nil
+	}
+
+	return time.Time{}, DayPrecision, fmt.Errorf("Release date '%s' is not valid.", value)
+}
+
+// readUpcomingRow parses band, album, type, genre, release date and added
+// date rows.
+func readUpcomingRow(row []string) (UpcomingRelease, error) {
+	var release UpcomingRelease
+
+	if len(row) < 6 {
+		return release, fmt.Errorf("Upcoming releases row has %d columns instead of 6.", len(row))
+	}
+
+	for _, link := range types.ReadBandLinks(row[0]) {
+		artist := ReleaseArtist{Name: link.Name, URL: link.URL}
+		artist.ID, _ = strconv.Atoi(link.ID)
+		release.Artists = append(release.Artists, artist)
+	}
+	albumMatch := latestAlbumre.FindStringSubmatch(row[1])
+	if len(release.Artists) == 0 || albumMatch == nil {
+		return release, fmt.Errorf("Upcoming releases row has no album or artist link.")
+	}
+
+	release.Album.Artist = release.Artists[0].Name
+	release.Album.ArtistURL = release.Artists[0].URL
+	release.Album.ArtistID = release.Artists[0].ID
+	release.Album.URL = albumMatch[1]
+	release.Album.ID, _ = strconv.Atoi(albumMatch[2])
+	release.Album.Name = html.UnescapeString(albumMatch[3])
+	release.Album.Type = types.SelectRecordType(types.CellText(row[2]))
+	release.Genre = types.CellText(row[3])
+
+	date, precision, err := ParseReleaseDate(row[4])
+	if err != nil {
+		return release, err
+	}
+	release.Date = date
+	release.Precision = precision
+	release.Album.Year = date.Year()
+
+	// Added date is informative, releases without it are kept.
+	for _, layout := range []string{types.ChangeTimeLayout, "2006-01-02"} {
+		if added, addedErr := time.Parse(layout, types.CellText(row[5])); addedErr == nil {
+			release.Added = added
+			break
+		}
+	}
+
+	return release, nil
+}
+
+// GetUpcomingReleases returns every release listed in the upcoming releases
+// calendar.
+func GetUpcomingReleases(ctx context.Context, client scraper.Client) ([]UpcomingRelease, error) {
+	var releases []UpcomingRelease
+
+	data, err := client.FetchAjaxPages(ctx, scraper.LatestEndpoint, "/release/ajax-upcoming/json/1", 0, 0)
+	if err != nil {
+		return releases, err
+	}
+
+	for _, row := range data.Data {
+		release, rowErr := readUpcomingRow(row)
+		if rowErr != nil {
+			return releases, &types.ParseError{What: "upcoming releases", Err: rowErr}
+		}
+		releases = append(releases, release)
+	}
+
+	return releases, nil
+}
@@ -0,0 +1,190 @@
+// +build integration_tests unit_tests
+
+package albums
+
+import (
+	"bytes"
+	"context"
+	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const upcomingReleases = `
+{
+	"error": "",
+	"iTotalRecords": 3,
+	"iTotalDisplayRecords": 3,
+	"sEcho": 0,
+	"aaData": [
+		[
+			"<a href=\"https://www.metal-archives.com/bands/Darkthrone/146\">Darkthrone</a>",
+			"<a href=\"https://www.metal-archives.com/albums/Darkthrone/It_Beckons_Us_All/1234\">It Beckons Us All</a>",
+			"Full-length",
+			"Black Metal, Heavy Metal",
+			"April 26th, 2024",
+			"2024-02-14 10:11:12"
+		],
+		[
+			"<a href=\"https://www.metal-archives.com/bands/Mayhem/67\">Mayhem</a> / <a href=\"https://www.metal-archives.com/bands/Burzum/88\">Burzum</a>",
+			"<a href=\"https://www.metal-archives.com/albums/Mayhem_-_Burzum/Split%2C_Split/4321\">Split, Split</a>",
+			"Split",
+			"Black Metal",
+			"June 1st, 2024",
+			""
+		],
+		[
+			"<a href=\"https://www.metal-archives.com/bands/Emperor/30\">Emperor</a>",
+			"<a href=\"https://www.metal-archives.com/albums/Emperor/Untitled/5678\">Untitled</a>",
+			"Full-length",
+			"Symphonic Black Metal",
+			"2025",
+			"2024-03-01"
+		]
+	]
+}`
+
+func getUpcomingReleases(t *testing.T) []UpcomingRelease {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/release/ajax-upcoming/json/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(upcomingReleases))
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	releases, err := GetUpcomingReleases(context.Background(), client)
+	if err != nil {
+		t.Fatalf("GetUpcomingReleases shouldn't fail, error was '%s'.", err.Error())
+	}
+	return releases
+}
+
+func TestParseReleaseDate(t *testing.T) {
+	date, precision, err := ParseReleaseDate("November 22nd, 2024 <!-- 2024-11-22 -->")
+	if err != nil || precision != DayPrecision || !date.Equal(time.Date(2024, time.November, 22, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("'November 22nd, 2024' should be 2024-11-22, not '%s'.", date)
+	}
+
+	date, precision, err = ParseReleaseDate("November 2024")
+	if err != nil || precision != MonthPrecision || !date.Equal(time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("'November 2024' should be November 2024, not '%s'.", date)
+	}
+
+	date, precision, err = ParseReleaseDate("2025")
+	if err != nil || precision != YearPrecision || date.Year() != 2025 {
+		t.Errorf("'2025' should be 2025, not '%s'.", date)
+	}
+
+	if _, _, err = ParseReleaseDate("Soon"); err == nil {
+		t.Errorf("'Soon' shouldn't be a valid release date.")
+	}
+}
+
+func TestGetUpcomingReleases(t *testing.T) {
+	releases := getUpcomingReleases(t)
+
+	if len(releases) != 3 {
+		t.Fatalf("Every upcoming release should be returned, returned %d.", len(releases))
+	}
+
+	if releases[0].Album.Name != "It Beckons Us All" || releases[0].Album.ID != 1234 || releases[0].Album.Type != commontypes.FullLength || releases[0].Genre != "Black Metal, Heavy Metal" {
+		t.Errorf("First release is wrong, found '%v'.", releases[0])
+	}
+
+	if !releases[0].Date.Equal(time.Date(2024, time.April, 26, 0, 0, 0, 0, time.UTC)) || !releases[0].Added.Equal(time.Date(2024, time.February, 14, 10, 11, 12, 0, time.UTC)) {
+		t.Errorf("First release should be added on 2024-02-14 for 2024-04-26, not '%s' for '%s'.", releases[0].Added, releases[0].Date)
+	}
+
+	if len(releases[1].Artists) != 2 || releases[1].Album.ArtistID != 67 || releases[1].Artists[1].ID != 88 || !releases[1].Added.IsZero() {
+		t.Errorf("Split should list both bands, found '%v'.", releases[1])
+	}
+
+	if releases[2].Precision != YearPrecision || releases[2].Album.Year != 2025 {
+		t.Errorf("Third release should only have a year, found '%v'.", releases[2])
+	}
+}
+
+func TestGetUpcomingReleasesBrokenDate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Replace(upcomingReleases, "April 26th, 2024", "Soon", 1)))
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	if _, err := GetUpcomingReleases(context.Background(), client); err == nil {
+		t.Errorf("Releases with invalid dates should fail.")
+	}
+}
+
+func TestWriteCalendar(t *testing.T) {
+	releases := getUpcomingReleases(t)
+
+	var calendar bytes.Buffer
+	if err := WriteCalendar(&calendar, releases, nil); err != nil {
+		t.Fatalf("WriteCalendar shouldn't fail, error was '%s'.", err.Error())
+	}
+	ics := calendar.String()
+
+	if !strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(ics, "END:VCALENDAR\r\n") {
+		t.Errorf("Calendar should be a CRLF iCalendar, found '%s'.", ics)
+	}
+
+	if strings.Count(ics, "BEGIN:VEVENT") != 3 {
+		t.Errorf("Every release should be written, found '%s'.", ics)
+	}
+	unfolded := strings.Replace(ics, "\r\n ", "", -1)
+
+	for _, expected := range []string{
+		"UID:1234@metal-archives.com\r\nDTSTAMP:20240214T101112Z\r\nDTSTART;VALUE=DATE:20240426\r\nDTEND;VALUE=DATE:20240427\r\n",
+		"SUMMARY:Darkthrone - It Beckons Us All\r\n",
+		"DESCRIPTION:Black Metal\\, Heavy Metal\r\n",
+		"SUMMARY:Mayhem / Burzum - Split\\, Split\r\n",
+		"UID:5678@metal-archives.com\r\nDTSTAMP:20240301T000000Z\r\nDTSTART;VALUE=DATE:20250101\r\nDTEND;VALUE=DATE:20250102\r\n",
+		"SUMMARY:Emperor - Untitled (2025)\r\n",
+		"DESCRIPTION:Symphonic Black Metal\\nRelease date is approximate\\, upstream only announces 2025.\r\n",
+	} {
+		if !strings.Contains(unfolded, expected) {
+			t.Errorf("Calendar should contain '%s', found '%s'.", expected, ics)
+		}
+	}
+
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Calendar lines should be folded at 75 octets, found '%s'.", line)
+		}
+	}
+}
+
+func TestWriteCalendarWatched(t *testing.T) {
+	releases := getUpcomingReleases(t)
+
+	var calendar bytes.Buffer
+	if err := WriteCalendar(&calendar, releases, []string{"88", "30"}); err != nil {
+		t.Fatalf("WriteCalendar shouldn't fail, error was '%s'.", err.Error())
+	}
+	ics := calendar.String()
+
+	if strings.Count(ics, "BEGIN:VEVENT") != 2 || !strings.Contains(ics, "UID:4321@metal-archives.com") || !strings.Contains(ics, "UID:5678@metal-archives.com") {
+		t.Errorf("Only the split and the release with a watched band should be written, found '%s'.", ics)
+	}
+}
+
+func TestFoldCalendarLine(t *testing.T) {
+	folded := foldCalendarLine("DESCRIPTION:" + strings.Repeat("ö", 70))
+	lines := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n ")
+
+	if len(lines) != 3 || len(lines[0]) > 75 || len(lines[1]) > 74 || strings.Join(lines, "") != "DESCRIPTION:"+strings.Repeat("ö", 70) {
+		t.Errorf("Long lines should be folded keeping characters whole, found '%s'.", folded)
+	}
+}
@@ -0,0 +1,120 @@
+package artists
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+)
+
+// BandStatus values are the ones used by upstream advanced search.
+type BandStatus int
+
+const (
+	ActiveStatus BandStatus = iota + 1
+	OnHoldStatus
+	SplitUpStatus
+	UnknownStatus
+	ChangedNameStatus
+	DisputedStatus
+)
+
+var bandStatusNames = map[BandStatus]string{
+	ActiveStatus:      "Active",
+	OnHoldStatus:      "On hold",
+	SplitUpStatus:     "Split-up",
+	UnknownStatus:     "Unknown",
+	ChangedNameStatus: "Changed name",
+	DisputedStatus:    "Disputed",
+}
+
+func (status BandStatus) String() string {
+	return bandStatusNames[status]
+}
+
+// ParseBandStatus reads status as shown on band pages.
+func ParseBandStatus(status string) (BandStatus, error) {
+	for bandStatus, name := range bandStatusNames {
+		if strings.EqualFold(name, strings.TrimSpace(status)) {
+			return bandStatus, nil
+		}
+	}
+	return 0, fmt.Errorf("Band status '%s' is not known.", status)
+}
+
+// AdvancedSearchCriteria holds the advanced band search filters, zero values
+// are not sent upstream.
+type AdvancedSearchCriteria struct {
+	Name       string
+	ExactName  bool
+	Genre      string
+	Countries  []string
+	FormedFrom int
+	FormedTo   int
+	Statuses   []BandStatus
+	Location   string
+	Themes     string
+}
+
+func (criteria AdvancedSearchCriteria) query() url.Values {
+	query := url.Values{}
+
+	if criteria.Name != "" {
+		query.Set("bandName", criteria.Name)
+		if criteria.ExactName {
+			query.Set("exactBandMatch", "1")
+		}
+	}
+	if criteria.Genre != "" {
+		query.Set("genre", criteria.Genre)
+	}
+	for _, country := range criteria.Countries {
+		query.Add("country[]", country)
+	}
+	if criteria.FormedFrom > 0 {
+		query.Set("yearCreationFrom", strconv.Itoa(criteria.FormedFrom))
+	}
+	if criteria.FormedTo > 0 {
+		query.Set("yearCreationTo", strconv.Itoa(criteria.FormedTo))
+	}
+	for _, status := range criteria.Statuses {
+		query.Add("status[]", strconv.Itoa(int(status)))
+	}
+	if criteria.Location != "" {
+		query.Set("location", criteria.Location)
+	}
+	if criteria.Themes != "" {
+		query.Set("themes", criteria.Themes)
+	}
+
+	return query
+}
+
+// AdvancedSearchArtists returns bands matching criteria starting at row
+// start, up to maxResults bands. maxResults lower than 1 means client
+// MaxResults.
+func AdvancedSearchArtists(ctx context.Context, client scraper.Client, criteria AdvancedSearchCriteria, start int, maxResults int) (SearchArtistResults, error) {
+	var results SearchArtistResults
+
+	query := criteria.query()
+	if len(query) == 0 {
+		return results, fmt.Errorf("Advanced search criteria is empty.")
+	}
+	if criteria.FormedFrom > 0 && criteria.FormedTo > 0 && criteria.FormedFrom > criteria.FormedTo {
+		return results, fmt.Errorf("Advanced search formation year range %d-%d is not valid.", criteria.FormedFrom, criteria.FormedTo)
+	}
+	if maxResults < 1 {
+		maxResults = client.MaxResults
+	}
+
+	searchURL := "/search/ajax-advanced/searching/bands/?" + query.Encode()
+	data, err := client.FetchAjaxPages(ctx, scraper.SearchEndpoint, searchURL, start, maxResults)
+	if err != nil {
+		return results, err
+	}
+
+	return readSearchArtistResults(data)
+}
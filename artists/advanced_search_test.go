@@ -0,0 +1,97 @@
+// +build integration_tests unit_tests
+
+package artists
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestAdvancedSearchCriteriaQuery(t *testing.T) {
+	criteria := AdvancedSearchCriteria{
+		Genre:      "black",
+		Countries:  []string{"NO", "SE"},
+		FormedFrom: 1990,
+		FormedTo:   1995,
+		Statuses:   []BandStatus{ActiveStatus, SplitUpStatus},
+		Location:   "Bergen",
+		Themes:     "Paganism",
+	}
+
+	query := criteria.query()
+
+	if query.Get("genre") != "black" || query.Get("location") != "Bergen" || query.Get("themes") != "Paganism" {
+		t.Errorf("Genre, location and themes should be sent, query was '%s'.", query.Encode())
+	}
+
+	if len(query["country[]"]) != 2 || query["country[]"][1] != "SE" {
+		t.Errorf("Every country should be sent, query was '%s'.", query.Encode())
+	}
+
+	if query.Get("yearCreationFrom") != "1990" || query.Get("yearCreationTo") != "1995" {
+		t.Errorf("Formation year range should be sent, query was '%s'.", query.Encode())
+	}
+
+	if len(query["status[]"]) != 2 || query["status[]"][0] != "1" || query["status[]"][1] != "3" {
+		t.Errorf("Statuses should be sent as upstream IDs, query was '%s'.", query.Encode())
+	}
+
+	if _, found := query["bandName"]; found {
+		t.Errorf("Empty band name shouldn't be sent, query was '%s'.", query.Encode())
+	}
+}
+
+func TestAdvancedSearchArtistsEmptyCriteria(t *testing.T) {
+	client := scraper.NewClient(http.Client{})
+
+	_, err := AdvancedSearchArtists(context.Background(), client, AdvancedSearchCriteria{}, 0, 0)
+
+	if err == nil || err.Error() != "Advanced search criteria is empty." {
+		t.Errorf("Empty criteria should fail with 'Advanced search criteria is empty.', not '%v'.", err)
+	}
+}
+
+func TestAdvancedSearchArtistsPaginated(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		start, _ := strconv.Atoi(query.Get("iDisplayStart"))
+		length, _ := strconv.Atoi(query.Get("iDisplayLength"))
+
+		page := types.SearchAjaxData{TotalRecords: 300, TotalDisplayRecords: 300, Data: [][]string{}}
+		for row := start; row < start+length && row < 300; row++ {
+			page.Data = append(page.Data, []string{fmt.Sprintf(`<a href="https://www.metal-archives.com/bands/Band_%d/%d">Band %d</a>`, row, row, row), "Black Metal", "Norway"})
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	results, err := AdvancedSearchArtists(context.Background(), client, AdvancedSearchCriteria{Genre: "black", Countries: []string{"NO"}}, 100, 150)
+
+	if err != nil {
+		t.Fatalf("AdvancedSearchArtists shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(results.Artists) != 150 || results.TotalRecords != 300 {
+		t.Errorf("AdvancedSearchArtists should return 150 of 300 artists, it returned %d of %d.", len(results.Artists), results.TotalRecords)
+	}
+
+	if results.Artists[0].ID != "100" || results.Artists[0].Name != "Band 100" || results.Artists[0].Country != "Norway" {
+		t.Errorf("First artist should be 'Band 100' from Norway, not '%s' from '%s'.", results.Artists[0].Name, results.Artists[0].Country)
+	}
+
+	if query.Get("genre") != "black" || query.Get("country[]") != "NO" {
+		t.Errorf("Criteria should be sent upstream, query was '%s'.", query.Encode())
+	}
+}
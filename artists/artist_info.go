@@ -1,83 +1,206 @@
 package artists
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
 	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
 	"golang.org/x/net/html"
-	"io/ioutil"
-	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
 
-func readRecord(n *html.Node) commontypes.Record {
+// readRecord parses the cells of a discography row: name, type and year.
+func readRecord(cells []*html.Node) (commontypes.Record, error) {
 	recordIDre := regexp.MustCompile(`^[^\/]*\/\/[^\/]*\/albums\/[^\/]*\/[^\/]*\/([0-9]*)$`)
 	var newRecord commontypes.Record
 
-	RecordInfo := n.FirstChild.NextSibling.FirstChild
+	RecordInfo := types.FindElement(cells[0], "a", nil)
+	if RecordInfo == nil {
+		return newRecord, fmt.Errorf("Discography row has no record link.")
+	}
 
-	newRecord.URL = RecordInfo.Attr[0].Val
-	RecordNameInfo := RecordInfo.FirstChild
-	newRecord.Name = RecordNameInfo.Data
+	newRecord.URL = types.Attribute(RecordInfo, "href")
+	newRecord.Name = types.Text(RecordInfo)
 	match := recordIDre.FindAllStringSubmatch(newRecord.URL, -1)
+	if match == nil {
+		return newRecord, fmt.Errorf("Discography record URL '%s' has no ID.", newRecord.URL)
+	}
 	newRecord.ID = match[0][1]
-	RecordTypeInfo := n.FirstChild.NextSibling.NextSibling.NextSibling.FirstChild
 
-	newRecord.Type = types.SelectRecordType(RecordTypeInfo.Data)
+	newRecord.Type = types.SelectRecordType(types.Text(cells[1]))
+
+	newRecord.Year, _ = strconv.Atoi(types.Text(cells[2]))
+
+	return newRecord, nil
+}
+
+var reviewsre = regexp.MustCompile(`^([0-9]+) \(([0-9]+)%\)$`)
+
+// readReviews parses a discography reviews cell such as "9 (92%)", records
+// without reviews have an empty cell.
+func readReviews(cell *html.Node, record *DiscographyRecord) {
+	match := reviewsre.FindStringSubmatch(types.Text(cell))
+	if match == nil {
+		return
+	}
+	record.ReviewCount, _ = strconv.Atoi(match[1])
+	record.ReviewAverage, _ = strconv.Atoi(match[2])
+	if link := types.FindElement(cell, "a", nil); link != nil {
+		record.ReviewsURL = types.Attribute(link, "href")
+	}
+}
+
+// DiscographyRecord is a discography row, ReviewAverage is the average
+// review percentage.
+type DiscographyRecord struct {
+	commontypes.Record
+	ReviewCount   int
+	ReviewAverage int
+	ReviewsURL    string
+}
+
+// DiscographyTab is one of the band page discography tabs.
+type DiscographyTab string
+
+const (
+	AllDiscography   DiscographyTab = "all"
+	MainDiscography  DiscographyTab = "main"
+	LivesDiscography DiscographyTab = "lives"
+	DemosDiscography DiscographyTab = "demos"
+	MiscDiscography  DiscographyTab = "misc"
+)
+
+// DiscographyFilter keeps records of any of Types released between FromYear
+// and ToYear, zero values do not filter.
+type DiscographyFilter struct {
+	Types    []commontypes.RecordType
+	FromYear int
+	ToYear   int
+}
+
+func (filter DiscographyFilter) keep(record commontypes.Record) bool {
+	if filter.FromYear > 0 && record.Year < filter.FromYear {
+		return false
+	}
+	if filter.ToYear > 0 && record.Year > filter.ToYear {
+		return false
+	}
+	if len(filter.Types) == 0 {
+		return true
+	}
+	for _, recordType := range filter.Types {
+		if record.Type == recordType {
+			return true
+		}
+	}
+	return false
+}
+
+// Discography holds the records listed in Tab.
+type Discography struct {
+	Tab     DiscographyTab
+	Records []DiscographyRecord
+}
+
+// RankByRating returns records with at least minReviews reviews sorted by
+// average review, then by review count. Ties keep discography order.
+func (discography Discography) RankByRating(minReviews int) []DiscographyRecord {
+	var ranked []DiscographyRecord
+	for _, record := range discography.Records {
+		if record.ReviewCount >= minReviews {
+			ranked = append(ranked, record)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].ReviewAverage != ranked[j].ReviewAverage {
+			return ranked[i].ReviewAverage > ranked[j].ReviewAverage
+		}
+		return ranked[i].ReviewCount > ranked[j].ReviewCount
+	})
 
-	RecordYearInfo := n.FirstChild.NextSibling.NextSibling.NextSibling.NextSibling.NextSibling.FirstChild
-	newRecord.Year, _ = strconv.Atoi(RecordYearInfo.Data)
+	return ranked
+}
 
-	return newRecord
+// TopRated returns up to n records with at least one review ranked by rating,
+// none when n is not positive.
+func (discography Discography) TopRated(n int) []DiscographyRecord {
+	if n <= 0 {
+		return nil
+	}
+	ranked := discography.RankByRating(1)
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
 }
 
-func GetArtistRecords(client http.Client, artistData SearchArtistData) ([]commontypes.Record, error) {
+func GetArtistRecords(client scraper.Client, artistData SearchArtistData) ([]commontypes.Record, error) {
+	return GetArtistRecordsWithContext(context.Background(), client, artistData)
+}
 
+func GetArtistRecordsWithContext(ctx context.Context, client scraper.Client, artistData SearchArtistData) ([]commontypes.Record, error) {
 	var records []commontypes.Record
-	url := fmt.Sprintf("https://www.metal-archives.com/band/discography/id/%s/tab/all", artistData.ID)
-	trCounter := 0
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return records, err
+
+	discography, err := GetArtistDiscography(ctx, client, artistData, AllDiscography, DiscographyFilter{})
+	for _, record := range discography.Records {
+		records = append(records, record.Record)
 	}
 
-	req.Header.Set("User-Agent", "https://github.com/a-castellano/metal-archives-wrapper")
+	return records, err
+}
+
+// GetArtistDiscography retrieves the records listed in artistData discography
+// tab which filter keeps.
+func GetArtistDiscography(ctx context.Context, client scraper.Client, artistData SearchArtistData, tab DiscographyTab, filter DiscographyFilter) (Discography, error) {
 
-	res, getErr := client.Do(req)
-	if getErr != nil {
-		return records, err
+	discography := Discography{Tab: tab}
+	switch tab {
+	case AllDiscography, MainDiscography, LivesDiscography, DemosDiscography, MiscDiscography:
+	default:
+		return discography, fmt.Errorf("Discography tab '%s' is not valid.", tab)
 	}
 
-	body, readErr := ioutil.ReadAll(res.Body)
-	if readErr != nil {
-		return records, err
+	url := fmt.Sprintf("/band/discography/id/%s/tab/%s", artistData.ID, tab)
+
+	body, getErr := client.GetWithContext(ctx, scraper.DiscographyEndpoint, url)
+	if getErr != nil {
+		return discography, getErr
 	}
 	stringBody := string(body)
 	doc, err := html.Parse(strings.NewReader(stringBody))
 	if err != nil {
-		return records, err
-	}
-	var f func(*html.Node, *[]commontypes.Record)
-	f = func(n *html.Node, records *[]commontypes.Record) {
-		if n.Type == html.ElementNode && n.Data == "tr" {
-			if trCounter != 0 {
-				newRecord := readRecord(n)
-				*records = append(*records, newRecord)
-			}
-			trCounter++
+		return discography, &types.ParseError{What: "discography", Err: err}
+	}
+	// Header and "Nothing entered yet" rows have no record cells.
+	found := false
+	for _, row := range types.FindElements(doc, "tr", nil) {
+		cells := types.ChildElements(row, "td")
+		if len(cells) < 3 {
+			continue
 		}
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			f(c, records)
+		newRecord, recordErr := readRecord(cells)
+		if recordErr != nil {
+			return discography, &types.ParseError{What: "discography", Err: recordErr}
+		}
+		found = true
+		if filter.keep(newRecord) {
+			record := DiscographyRecord{Record: newRecord}
+			if len(cells) > 3 {
+				readReviews(cells[3], &record)
+			}
+			discography.Records = append(discography.Records, record)
 		}
 	}
-	f(doc, &records)
 
-	if len(records) == 0 {
-		return records, errors.New("No records were found.")
+	// A filter keeping no records is not an error, an empty tab is.
+	if !found {
+		return discography, &types.NoMatchError{Message: "No records were found."}
 	}
 
-	return records, nil
+	return discography, nil
 }
@@ -4,9 +4,16 @@ package artists
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -14,9 +21,9 @@ func TestGetArtistRecordBroken(t *testing.T) {
 
 	artistData := SearchArtistData{Name: "Bölzer", URL: "https://www.metal-archives.com/bands/B%C3%B6lzer/3540351548", ID: "3540351548", Genre: "Black/Death Metal", Country: "Switzerland"}
 
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 not html code
-	`))}}}
+	`))}}})
 
 	records, err := GetArtistRecords(client, artistData)
 
@@ -33,7 +40,7 @@ func TestGetArtistRecords(t *testing.T) {
 
 	artistData := SearchArtistData{Name: "Bölzer", URL: "https://www.metal-archives.com/bands/B%C3%B6lzer/3540351548", ID: "3540351548", Genre: "Black/Death Metal", Country: "Switzerland"}
 
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
  <table width="100%" cellpadding="0" cellspacing="0" class="display discog">
 <thead>
 <tr>
@@ -94,7 +101,7 @@ func TestGetArtistRecords(t *testing.T) {
 </tr>
 </tbody>
 </table>
-	`))}}}
+	`))}}})
 
 	records, err := GetArtistRecords(client, artistData)
 
@@ -134,7 +141,7 @@ func TestGetArtistMoreRecords(t *testing.T) {
 
 	artistData := SearchArtistData{Name: "Hypocrisy", URL: "https://www.metal-archives.com/bands/Hypocrisy/96", ID: "96", Genre: "Death Metal (early), Melodic Death Metal (later)", Country: "Sweden"}
 
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
  <table width="100%" cellpadding="0" cellspacing="0" class="display discog">
 <thead>
 <tr>
@@ -451,7 +458,7 @@ func TestGetArtistMoreRecords(t *testing.T) {
 </tr>
 </tbody>
 </table>
-	`))}}}
+	`))}}})
 
 	records, err := GetArtistRecords(client, artistData)
 
@@ -469,3 +476,187 @@ func TestGetArtistMoreRecords(t *testing.T) {
 		t.Errorf(`'Nuclear Blast Festivals 2000' record type should be Other.`)
 	}
 }
+
+func TestGetArtistRecordsUnexpectedHTML(t *testing.T) {
+
+	artistData := SearchArtistData{Name: "Bölzer", URL: "https://www.metal-archives.com/bands/B%C3%B6lzer/3540351548", ID: "3540351548", Genre: "Black/Death Metal", Country: "Switzerland"}
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+<table>
+<tr><td>Unexpected</td><td>table</td><td>content</td></tr>
+</table>
+	`))}}})
+
+	_, err := GetArtistRecords(client, artistData)
+
+	if !errors.Is(err, types.ErrParse) {
+		t.Errorf("GetArtistRecords should return ErrParse when discography rows are unexpected, not '%v'.", err)
+	}
+}
+
+func TestGetArtistRecordsChallengePage(t *testing.T) {
+
+	artistData := SearchArtistData{Name: "Bölzer", URL: "https://www.metal-archives.com/bands/B%C3%B6lzer/3540351548", ID: "3540351548", Genre: "Black/Death Metal", Country: "Switzerland"}
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{StatusCode: http.StatusForbidden, Body: ioutil.NopCloser(bytes.NewBufferString(`
+<!DOCTYPE html><html><head><title>Just a moment...</title></head><body><table><tr><td></td></tr></table></body></html>
+	`))}}})
+
+	_, err := GetArtistRecords(client, artistData)
+
+	if !errors.Is(err, types.ErrBlocked) {
+		t.Errorf("GetArtistRecords should return ErrBlocked when upstream serves a challenge page, not '%v'.", err)
+	}
+}
+
+func TestGetArtistDiscographyTabAndFilter(t *testing.T) {
+
+	artistData := SearchArtistData{Name: "Bölzer", ID: "3540351548"}
+
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		if strings.HasSuffix(r.URL.Path, "/tab/demos") {
+			fmt.Fprint(w, `
+<table class="display discog">
+<tbody>
+<tr>
+<td colspan="4"><em>Nothing entered yet. Please add the releases, if applicable. </em></td>
+</tr>
+</tbody>
+</table>`)
+			return
+		}
+		fmt.Fprint(w, `
+<table class="display discog">
+<tbody>
+<tr>
+<td><a href="https://www.metal-archives.com/albums/B%C3%B6lzer/Aura/376088" class="other">Aura</a></td>
+<td class="other">EP</td>
+<td class="other">2013</td>
+</tr>
+<tr>
+<td><a href="https://www.metal-archives.com/albums/B%C3%B6lzer/Soma/447710" class="other">Soma</a></td>
+<td class="other">EP</td>
+<td class="other">2014</td>
+</tr>
+<tr>
+<td><a href="https://www.metal-archives.com/albums/B%C3%B6lzer/Hero/604091" class="album">Hero</a></td>
+<td class="album">Full-length</td>
+<td class="album">2016</td>
+</tr>
+</tbody>
+</table>`)
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	discography, err := GetArtistDiscography(context.Background(), client, artistData, MainDiscography, DiscographyFilter{Types: []commontypes.RecordType{commontypes.EP}, FromYear: 2014})
+
+	if err != nil {
+		t.Fatalf("GetArtistDiscography shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if requestedPath != "/band/discography/id/3540351548/tab/main" {
+		t.Errorf("Main tab should be requested, not '%s'.", requestedPath)
+	}
+
+	if discography.Tab != MainDiscography {
+		t.Errorf("Discography should come from main tab, not '%s'.", discography.Tab)
+	}
+
+	if len(discography.Records) != 1 || discography.Records[0].Name != "Soma" {
+		t.Errorf("Only EP 'Soma' should be kept, found '%v'.", discography.Records)
+	}
+
+	discography, err = GetArtistDiscography(context.Background(), client, artistData, MainDiscography, DiscographyFilter{ToYear: 2000})
+
+	if err != nil || len(discography.Records) != 0 {
+		t.Errorf("GetArtistDiscography should return no records without error when filter keeps none, not '%v' and '%v'.", discography.Records, err)
+	}
+
+	_, err = GetArtistDiscography(context.Background(), client, artistData, DemosDiscography, DiscographyFilter{})
+
+	if !errors.Is(err, types.ErrNoMatch) {
+		t.Errorf("GetArtistDiscography should return ErrNoMatch when tab has no records, not '%v'.", err)
+	}
+
+	_, err = GetArtistDiscography(context.Background(), client, artistData, DiscographyTab("singles"), DiscographyFilter{})
+
+	if err == nil || err.Error() != "Discography tab 'singles' is not valid." {
+		t.Errorf("Unknown tabs should fail with 'Discography tab 'singles' is not valid.', not '%v'.", err)
+	}
+}
+
+func TestGetArtistDiscographyReviews(t *testing.T) {
+
+	artistData := SearchArtistData{Name: "Bölzer", ID: "3540351548"}
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+<table class="display discog">
+<tbody>
+<tr>
+<td><a href="https://www.metal-archives.com/albums/B%C3%B6lzer/Roman_Acupuncture/350987" class="demo">Roman Acupuncture</a></td>
+<td class="demo">Demo</td>
+<td class="demo">2012</td>
+<td>
+<a href="https://www.metal-archives.com/reviews/B%C3%B6lzer/Roman_Acupuncture/350987/">2 (92%)</a>
+</td>
+</tr>
+<tr>
+<td><a href="https://www.metal-archives.com/albums/B%C3%B6lzer/Aura/376088" class="other">Aura</a></td>
+<td class="other">EP</td>
+<td class="other">2013</td>
+<td>
+<a href="https://www.metal-archives.com/reviews/B%C3%B6lzer/Aura/376088/">9 (92%)</a>
+</td>
+</tr>
+<tr>
+<td><a href="https://www.metal-archives.com/albums/B%C3%B6lzer/Hero/604091" class="album">Hero</a></td>
+<td class="album">Full-length</td>
+<td class="album">2016</td>
+<td>
+<a href="https://www.metal-archives.com/reviews/B%C3%B6lzer/Hero/604091/">6 (63%)</a>
+</td>
+</tr>
+<tr>
+<td><a href="https://www.metal-archives.com/albums/B%C3%B6lzer/Lese_Majeste/1001" class="other">Lese Majeste</a></td>
+<td class="other">EP</td>
+<td class="other">2023</td>
+<td>&nbsp;</td>
+</tr>
+</tbody>
+</table>
+	`))}}})
+
+	discography, err := GetArtistDiscography(context.Background(), client, artistData, AllDiscography, DiscographyFilter{})
+
+	if err != nil {
+		t.Fatalf("GetArtistDiscography shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	aura := discography.Records[1]
+	if aura.ReviewCount != 9 || aura.ReviewAverage != 92 || aura.ReviewsURL != "https://www.metal-archives.com/reviews/B%C3%B6lzer/Aura/376088/" {
+		t.Errorf("Aura should have 9 reviews averaging 92%%, not %d averaging %d%%.", aura.ReviewCount, aura.ReviewAverage)
+	}
+
+	if discography.Records[3].ReviewCount != 0 || discography.Records[3].ReviewsURL != "" {
+		t.Errorf("Lese Majeste shouldn't have reviews.")
+	}
+
+	ranked := discography.RankByRating(0)
+	if len(ranked) != 4 || ranked[0].Name != "Aura" || ranked[1].Name != "Roman Acupuncture" || ranked[3].Name != "Lese Majeste" {
+		t.Errorf("Records should be ranked by average and then by review count, not '%v'.", ranked)
+	}
+
+	top := discography.TopRated(5)
+	if len(top) != 3 || top[2].Name != "Hero" {
+		t.Errorf("Top rated records should only include reviewed ones, not '%v'.", top)
+	}
+
+	if top := discography.TopRated(-1); len(top) != 0 {
+		t.Errorf("Top rated records should be empty for negative amounts, not '%v'.", top)
+	}
+}
@@ -0,0 +1,216 @@
+package artists
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"golang.org/x/net/html"
+)
+
+// LineupTab is one of the band page members tabs.
+type LineupTab string
+
+const (
+	CompleteLineup LineupTab = "all"
+	CurrentLineup  LineupTab = "current"
+	PastLineup     LineupTab = "past"
+	LiveLineup     LineupTab = "live"
+)
+
+var lineupNames = map[LineupTab]string{
+	CompleteLineup: "Complete",
+	CurrentLineup:  "Current",
+	PastLineup:     "Past",
+	LiveLineup:     "Live",
+}
+
+type Role struct {
+	Name  string
+	Years []YearsActive
+}
+
+// OtherBand is a band listed under a member, URL and ID are empty when it has
+// no page upstream. Note holds the parenthesized remark following its name,
+// such as "live".
+type OtherBand struct {
+	Name   string
+	URL    string
+	ID     string
+	Former bool
+	Note   string
+}
+
+// Member is a lineup row, Lineup is the header it is listed under in the
+// complete tab ("Current", "Past", "Live"...) or the tab name in other tabs.
+type Member struct {
+	Name       string
+	URL        string
+	ID         string
+	Lineup     string
+	Roles      []Role
+	Years      []YearsActive
+	OtherBands []OtherBand
+}
+
+var (
+	memberIDre      = regexp.MustCompile(`/artists/[^/]*/([0-9]+)$`)
+	rolere          = regexp.MustCompile(`^([^(]*?)\s*(?:\(([^)]*)\))?$`)
+	otherBandNotere = regexp.MustCompile(`^(.+?)\s*\(([^()]*)\)$`)
+)
+
+// splitOutsideParens splits s by commas which are not inside parentheses.
+func splitOutsideParens(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+// ParseRoles reads roles such as "Guitars, Vocals (1991-2001), Keyboards",
+// the years of every role are also returned together.
+func ParseRoles(value string) ([]Role, []YearsActive, error) {
+	var roles []Role
+	var years []YearsActive
+
+	for _, part := range splitOutsideParens(value) {
+		match := rolere.FindStringSubmatch(part)
+		if match == nil {
+			return roles, years, fmt.Errorf("Lineup role '%s' is not valid.", part)
+		}
+		role := Role{Name: match[1]}
+		if match[2] != "" {
+			// Parentheses may hold notes such as "(session)" instead of years.
+			roleYears, err := ParseYearsActive(match[2])
+			if err != nil {
+				role.Name = part
+			}
+			role.Years = roleYears
+			years = append(years, roleYears...)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, years, nil
+}
+
+func readOtherBands(row *html.Node) []OtherBand {
+	var bands []OtherBand
+
+	links := make(map[string]*html.Node)
+	for _, link := range types.FindElements(row, "a", nil) {
+		links[types.Text(link)] = link
+	}
+
+	text := strings.TrimSpace(strings.TrimPrefix(types.Text(row), "See also:"))
+	for _, name := range splitOutsideParens(text) {
+		var band OtherBand
+		if strings.HasPrefix(name, "ex-") {
+			band.Former = true
+			name = strings.TrimPrefix(name, "ex-")
+		}
+		// Remarks are written after the link, names with parentheses are kept
+		// when a link holds them.
+		if _, found := links[name]; !found {
+			if match := otherBandNotere.FindStringSubmatch(name); match != nil {
+				name = match[1]
+				band.Note = match[2]
+			}
+		}
+		band.Name = name
+		if link, found := links[name]; found {
+			band.URL = types.Attribute(link, "href")
+			band.ID, _ = types.BandID(band.URL)
+		}
+		bands = append(bands, band)
+	}
+
+	return bands
+}
+
+func readLineup(panel *html.Node, tab LineupTab) ([]Member, error) {
+	var members []Member
+	lineup := lineupNames[tab]
+
+	for _, row := range types.FindElements(panel, "tr", nil) {
+		switch {
+		case types.HasClass(row, "lineupHeaders"):
+			lineup = types.Text(row)
+		case types.HasClass(row, "lineupRow"):
+			cells := types.ChildElements(row, "td")
+			link := types.FindElement(row, "a", nil)
+			if len(cells) < 2 || link == nil {
+				return members, fmt.Errorf("Lineup row has no member link.")
+			}
+			member := Member{Name: types.Text(link), URL: types.Attribute(link, "href"), Lineup: lineup}
+			if match := memberIDre.FindStringSubmatch(member.URL); match != nil {
+				member.ID = match[1]
+			}
+			roles, years, err := ParseRoles(types.Text(cells[1]))
+			if err != nil {
+				return members, err
+			}
+			member.Roles = roles
+			member.Years = years
+			members = append(members, member)
+		case types.HasClass(row, "lineupBandsRow"):
+			if len(members) > 0 {
+				members[len(members)-1].OtherBands = readOtherBands(row)
+			}
+		}
+	}
+
+	return members, nil
+}
+
+// GetArtistLineup retrieves band id members listed in tab.
+func GetArtistLineup(ctx context.Context, client scraper.Client, id string, tab LineupTab) ([]Member, error) {
+	var members []Member
+
+	body, err := client.GetWithContext(ctx, scraper.BandEndpoint, fmt.Sprintf("/bands/_/%s", id))
+	if err != nil {
+		return members, err
+	}
+
+	doc, err := html.Parse(strings.NewReader(string(body)))
+	if err != nil {
+		return members, &types.ParseError{What: "band lineup", Err: err}
+	}
+
+	if types.FindElement(doc, "div", types.WithID("band_members")) == nil {
+		return members, &types.ParseError{What: "band lineup", Err: fmt.Errorf("Band page has no members.")}
+	}
+
+	// Bands without members in a lineup have no tab for it.
+	panel := types.FindElement(doc, "div", types.WithID("band_tab_members_"+string(tab)))
+	if panel != nil {
+		members, err = readLineup(panel, tab)
+		if err != nil {
+			return members, &types.ParseError{What: "band lineup", Err: err}
+		}
+	}
+
+	if len(members) == 0 {
+		return members, &types.NoMatchError{Message: "No members were found."}
+	}
+
+	return members, nil
+}
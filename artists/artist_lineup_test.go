@@ -0,0 +1,144 @@
+// +build integration_tests unit_tests
+
+package artists
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+const emperorMembers = `
+<div id="band_members">
+	<ul>
+		<li><a href="#band_tab_members_all">Complete lineup</a></li>
+		<li><a href="#band_tab_members_current">Current lineup</a></li>
+		<li><a href="#band_tab_members_past">Past members</a></li>
+	</ul>
+	<div id="band_tab_members_all">
+		<div class="ui-tabs-panel-content">
+			<table class="display lineupTable" cellpadding="0" cellspacing="0">
+				<tr class="lineupHeaders"><td colspan="2">Current</td></tr>
+				<tr class="lineupRow">
+					<td width="200"><a href="https://www.metal-archives.com/artists/Ihsahn/1424" class="bold">Ihsahn</a></td>
+					<td>Guitars, Vocals (1991-2001, 2005-present), Keyboards (1991-1993)</td>
+				</tr>
+				<tr class="lineupBandsRow">
+					<td colspan="2">See also: <a href="https://www.metal-archives.com/bands/Peccatum/1452">Peccatum</a>, ex-<a href="https://www.metal-archives.com/bands/Thou_Shalt_Suffer/1425">Thou Shalt Suffer</a>, ex-Xerasia</td>
+				</tr>
+				<tr class="lineupHeaders"><td colspan="2">Past</td></tr>
+				<tr class="lineupRow">
+					<td width="200"><a href="https://www.metal-archives.com/artists/Faust/1428" class="bold">Faust</a></td>
+					<td>Drums (1992-1993)</td>
+				</tr>
+				<tr class="lineupBandsRow">
+					<td colspan="2">See also: ex-<a href="https://www.metal-archives.com/bands/Dissection/123">Dissection</a> (live)</td>
+				</tr>
+				<tr class="lineupHeaders"><td colspan="2">Live</td></tr>
+				<tr class="lineupRow">
+					<td width="200"><a href="https://www.metal-archives.com/artists/Einar_Solberg/85440" class="bold">Einar Solberg</a></td>
+					<td>Keyboards (session)</td>
+				</tr>
+			</table>
+		</div>
+	</div>
+	<div id="band_tab_members_current">
+		<div class="ui-tabs-panel-content">
+			<table class="display lineupTable" cellpadding="0" cellspacing="0">
+				<tr class="lineupRow">
+					<td width="200"><a href="https://www.metal-archives.com/artists/Ihsahn/1424" class="bold">Ihsahn</a></td>
+					<td>Guitars, Vocals (1991-2001, 2005-present), Keyboards (1991-1993)</td>
+				</tr>
+			</table>
+		</div>
+	</div>
+</div>
+`
+
+func TestGetArtistLineupComplete(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(emperorMembers))}}})
+
+	members, err := GetArtistLineup(context.Background(), client, "30", CompleteLineup)
+
+	if err != nil {
+		t.Fatalf("GetArtistLineup shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(members) != 3 {
+		t.Fatalf("Complete lineup should have 3 members, not %d.", len(members))
+	}
+
+	ihsahn := members[0]
+	if ihsahn.Name != "Ihsahn" || ihsahn.ID != "1424" || ihsahn.URL != "https://www.metal-archives.com/artists/Ihsahn/1424" || ihsahn.Lineup != "Current" {
+		t.Errorf("First member should be current member Ihsahn, not '%v'.", ihsahn)
+	}
+
+	if len(ihsahn.Roles) != 3 || ihsahn.Roles[0].Name != "Guitars" || ihsahn.Roles[1].Name != "Vocals" || ihsahn.Roles[2].Name != "Keyboards" {
+		t.Fatalf("Ihsahn roles should be guitars, vocals and keyboards, not '%v'.", ihsahn.Roles)
+	}
+
+	if len(ihsahn.Roles[1].Years) != 2 || !ihsahn.Roles[1].Years[1].Present || ihsahn.Roles[1].Years[0] != (YearsActive{From: 1991, To: 2001}) {
+		t.Errorf("Ihsahn vocals years should be 1991-2001 and 2005-present, not '%v'.", ihsahn.Roles[1].Years)
+	}
+
+	if len(ihsahn.Years) != 3 {
+		t.Errorf("Ihsahn should have 3 active periods, not '%v'.", ihsahn.Years)
+	}
+
+	if len(ihsahn.OtherBands) != 3 {
+		t.Fatalf("Ihsahn should have 3 other bands, not '%v'.", ihsahn.OtherBands)
+	}
+
+	if ihsahn.OtherBands[0] != (OtherBand{Name: "Peccatum", URL: "https://www.metal-archives.com/bands/Peccatum/1452", ID: "1452"}) {
+		t.Errorf("First other band should be current band Peccatum, not '%v'.", ihsahn.OtherBands[0])
+	}
+
+	if !ihsahn.OtherBands[1].Former || ihsahn.OtherBands[1].ID != "1425" {
+		t.Errorf("Second other band should be former band Thou Shalt Suffer, not '%v'.", ihsahn.OtherBands[1])
+	}
+
+	if ihsahn.OtherBands[2] != (OtherBand{Name: "Xerasia", Former: true}) {
+		t.Errorf("Third other band should be former band Xerasia without page, not '%v'.", ihsahn.OtherBands[2])
+	}
+
+	if len(members[1].OtherBands) != 1 || members[1].OtherBands[0] != (OtherBand{Name: "Dissection", URL: "https://www.metal-archives.com/bands/Dissection/123", ID: "123", Former: true, Note: "live"}) {
+		t.Errorf("Faust other band should be former live band Dissection, not '%v'.", members[1].OtherBands)
+	}
+
+	if members[1].Lineup != "Past" || members[2].Lineup != "Live" {
+		t.Errorf("Faust should be a past member and Einar Solberg a live one.")
+	}
+
+	if len(members[2].Roles) != 1 || members[2].Roles[0].Name != "Keyboards (session)" || len(members[2].Years) != 0 {
+		t.Errorf("Role notes should be kept in role name, not '%v'.", members[2].Roles)
+	}
+}
+
+func TestGetArtistLineupCurrent(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(emperorMembers))}}})
+
+	members, err := GetArtistLineup(context.Background(), client, "30", CurrentLineup)
+
+	if err != nil {
+		t.Fatalf("GetArtistLineup shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(members) != 1 || members[0].Lineup != "Current" {
+		t.Errorf("Current lineup should only have Ihsahn.")
+	}
+}
+
+func TestGetArtistLineupWithoutTab(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(emperorMembers))}}})
+
+	_, err := GetArtistLineup(context.Background(), client, "30", LiveLineup)
+
+	if !errors.Is(err, types.ErrNoMatch) {
+		t.Errorf("Missing lineup tab should return ErrNoMatch, not '%v'.", err)
+	}
+}
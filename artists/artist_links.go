@@ -0,0 +1,123 @@
+package artists
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"golang.org/x/net/html"
+)
+
+// LinkService is a known external site a related link points to.
+type LinkService string
+
+const (
+	UnknownService     LinkService = ""
+	MusicBrainzService LinkService = "musicbrainz"
+	DiscogsService     LinkService = "discogs"
+	BandcampService    LinkService = "bandcamp"
+	SpotifyService     LinkService = "spotify"
+	YouTubeService     LinkService = "youtube"
+	WikipediaService   LinkService = "wikipedia"
+)
+
+// Link is a band related link. Category is the upstream section it is listed
+// in, such as "Official" or "Official merchandise". ExternalID is the entity
+// ID on Service, empty when the service is unknown.
+type Link struct {
+	Category   string
+	Name       string
+	URL        string
+	Service    LinkService
+	ExternalID string
+}
+
+var (
+	musicBrainzIDre = regexp.MustCompile(`^/artist/([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})`)
+	discogsIDre     = regexp.MustCompile(`^(?:/[a-z]{2})?/artist/([0-9]+)`)
+	spotifyIDre     = regexp.MustCompile(`^/artist/([0-9A-Za-z]+)`)
+	youTubeIDre     = regexp.MustCompile(`^/(?:channel/|user/|c/)([^/]+)|^/(@[^/]+)`)
+	wikipediaIDre   = regexp.MustCompile(`^/wiki/([^/]+)`)
+)
+
+// hostIs tells whether host is domain or one of its subdomains.
+func hostIs(host string, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// DetectLinkService tells which known service rawURL points to and the ID of
+// the entity it links.
+func DetectLinkService(rawURL string) (LinkService, string) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return UnknownService, ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	path := parsed.EscapedPath()
+
+	find := func(re *regexp.Regexp) string {
+		if match := re.FindStringSubmatch(path); match != nil {
+			return strings.Join(match[1:], "")
+		}
+		return ""
+	}
+
+	switch {
+	case hostIs(host, "musicbrainz.org"):
+		return MusicBrainzService, find(musicBrainzIDre)
+	case hostIs(host, "discogs.com"):
+		return DiscogsService, find(discogsIDre)
+	case hostIs(host, "bandcamp.com"):
+		return BandcampService, strings.TrimSuffix(strings.TrimSuffix(host, "bandcamp.com"), ".")
+	case host == "open.spotify.com":
+		return SpotifyService, find(spotifyIDre)
+	case hostIs(host, "youtube.com"):
+		return YouTubeService, find(youTubeIDre)
+	case hostIs(host, "wikipedia.org"):
+		title, _ := url.PathUnescape(find(wikipediaIDre))
+		return WikipediaService, title
+	default:
+		return UnknownService, ""
+	}
+}
+
+// GetArtistLinks retrieves band id related links.
+func GetArtistLinks(ctx context.Context, client scraper.Client, id string) ([]Link, error) {
+	var links []Link
+
+	body, err := client.GetWithContext(ctx, scraper.BandEndpoint, fmt.Sprintf("/link/ajax-list/type/band/id/%s", id))
+	if err != nil {
+		return links, err
+	}
+
+	doc, err := html.Parse(strings.NewReader(string(body)))
+	if err != nil {
+		return links, &types.ParseError{What: "band links", Err: err}
+	}
+
+	sections := types.FindElements(doc, "div", func(n *html.Node) bool {
+		return strings.HasPrefix(types.Attribute(n, "id"), "band_links_")
+	})
+	for _, section := range sections {
+		category := strings.Replace(strings.TrimPrefix(types.Attribute(section, "id"), "band_links_"), "_", " ", -1)
+		for _, anchor := range types.FindElements(section, "a", nil) {
+			href := types.Attribute(anchor, "href")
+			if !strings.HasPrefix(href, "http") {
+				continue
+			}
+			link := Link{Category: category, Name: types.Text(anchor), URL: href}
+			link.Service, link.ExternalID = DetectLinkService(href)
+			links = append(links, link)
+		}
+	}
+
+	if len(links) == 0 {
+		return links, &types.NoMatchError{Message: "No links were found."}
+	}
+
+	return links, nil
+}
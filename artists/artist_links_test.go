@@ -0,0 +1,92 @@
+// +build integration_tests unit_tests
+
+package artists
+
+import (
+	"bytes"
+	"context"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestDetectLinkService(t *testing.T) {
+	links := map[string][2]string{
+		"https://musicbrainz.org/artist/82a7db3b-1d8d-4a58-8b4a-96ba1e4e6b0a":    {"musicbrainz", "82a7db3b-1d8d-4a58-8b4a-96ba1e4e6b0a"},
+		"https://www.discogs.com/artist/81993-Burzum":                            {"discogs", "81993"},
+		"https://www.discogs.com/es/artist/81993-Burzum":                         {"discogs", "81993"},
+		"https://burzum.bandcamp.com/":                                           {"bandcamp", "burzum"},
+		"https://open.spotify.com/artist/4Ab3ACSh9eWuNvLh7Tq6Nu":                 {"spotify", "4Ab3ACSh9eWuNvLh7Tq6Nu"},
+		"https://www.youtube.com/channel/UCwP9Lh2uB_qZGQxKR1xJc4A":               {"youtube", "UCwP9Lh2uB_qZGQxKR1xJc4A"},
+		"https://www.youtube.com/@burzumofficial":                                {"youtube", "@burzumofficial"},
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ":                            {"youtube", ""},
+		"https://en.wikipedia.org/wiki/Burzum":                                   {"wikipedia", "Burzum"},
+		"https://no.wikipedia.org/wiki/Burzum_%28band%29":                        {"wikipedia", "Burzum_(band)"},
+		"https://www.burzum.org/":                                                {"", ""},
+		"https://notmusicbrainz.org/artist/82a7db3b-1d8d-4a58-8b4a-96ba1e4e6b0a": {"", ""},
+	}
+
+	for link, expected := range links {
+		service, id := DetectLinkService(link)
+		if string(service) != expected[0] || id != expected[1] {
+			t.Errorf("'%s' should be service '%s' with ID '%s', not '%s' with ID '%s'.", link, expected[0], expected[1], service, id)
+		}
+	}
+}
+
+func TestGetArtistLinks(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+<div id="band_links">
+	<ul>
+		<li><a href="#band_links_Official">Official</a></li>
+		<li><a href="#band_links_Official_merchandise">Official merchandise</a></li>
+		<li><a href="#band_links_Unofficial">Unofficial</a></li>
+	</ul>
+	<div id="band_links_Official">
+		<table id="linksTableOfficial">
+			<tr id="header_Official"><td><span class="title">Official</span></td></tr>
+			<tr><td><a id="link1" href="https://www.burzum.org/" target="_blank" title="Go to: https://www.burzum.org/">Burzum.org</a></td></tr>
+			<tr><td><a id="link2" href="https://open.spotify.com/artist/4Ab3ACSh9eWuNvLh7Tq6Nu" target="_blank">Spotify</a></td></tr>
+		</table>
+	</div>
+	<div id="band_links_Official_merchandise">
+		<table id="linksTableOfficial_merchandise">
+			<tr><td><a id="link3" href="https://burzum.bandcamp.com/" target="_blank">Bandcamp</a></td></tr>
+		</table>
+	</div>
+	<div id="band_links_Unofficial">
+		<table id="linksTableUnofficial">
+			<tr><td><a id="link4" href="https://www.discogs.com/artist/81993-Burzum" target="_blank">Discogs</a></td></tr>
+			<tr><td><a href="#" onclick="return false;">Report broken link</a></td></tr>
+		</table>
+	</div>
+</div>
+`))}}})
+
+	links, err := GetArtistLinks(context.Background(), client, "88")
+
+	if err != nil {
+		t.Fatalf("GetArtistLinks shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(links) != 4 {
+		t.Fatalf("Burzum should have 4 links, not %d.", len(links))
+	}
+
+	if links[0] != (Link{Category: "Official", Name: "Burzum.org", URL: "https://www.burzum.org/"}) {
+		t.Errorf("First link should be official site without service, not '%v'.", links[0])
+	}
+
+	if links[1].Service != SpotifyService || links[1].ExternalID != "4Ab3ACSh9eWuNvLh7Tq6Nu" {
+		t.Errorf("Second link should be Spotify artist, not '%v'.", links[1])
+	}
+
+	if links[2].Category != "Official merchandise" || links[2].Service != BandcampService || links[2].ExternalID != "burzum" {
+		t.Errorf("Third link should be Bandcamp official merchandise, not '%v'.", links[2])
+	}
+
+	if links[3].Category != "Unofficial" || links[3].Service != DiscogsService || links[3].ExternalID != "81993" {
+		t.Errorf("Fourth link should be unofficial Discogs artist, not '%v'.", links[3])
+	}
+}
@@ -0,0 +1,232 @@
+package artists
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"golang.org/x/net/html"
+)
+
+// Profile fields, used as ArtistProfile Errors keys.
+const (
+	CountryField      = "Country of origin"
+	LocationField     = "Location"
+	StatusField       = "Status"
+	FormedInField     = "Formed in"
+	YearsActiveField  = "Years active"
+	GenreField        = "Genre"
+	ThemesField       = "Lyrical themes"
+	LabelField        = "Label"
+	LastModifiedField = "Last modified"
+)
+
+// YearsActive is a period the band was active, Name is set when the band
+// used another name during it. To is 0 when the end year is unknown.
+type YearsActive struct {
+	From    int
+	To      int
+	Present bool
+	Name    string
+}
+
+// ArtistProfile holds the band page stats. Fields which could not be parsed
+// are left empty and their error is stored in Errors.
+type ArtistProfile struct {
+	Name         string
+	URL          string
+	ID           string
+	Country      string
+	CountryCode  string
+	Location     string
+	Status       BandStatus
+	FormedIn     int
+	YearsActive  []YearsActive
+	Genre        string
+	Themes       string
+	Label        string
+	LabelURL     string
+	LastModified time.Time
+	LogoURL      string
+	PhotoURL     string
+	Errors       map[string]error
+}
+
+var (
+	countryCodere  = regexp.MustCompile(`/lists/([A-Z]{2})$`)
+	yearsActivere  = regexp.MustCompile(`([0-9]{4}|\?)(?:\s*-\s*([0-9]{4}|present|\?))?(?:\s*\(as ([^)]+)\))?`)
+	lastModifiedre = regexp.MustCompile(`Last modified on:?\s*([0-9]{4}-[0-9]{2}-[0-9]{2} [0-9]{2}:[0-9]{2}:[0-9]{2})`)
+)
+
+func (profile *ArtistProfile) fieldError(field string, err error) {
+	if profile.Errors == nil {
+		profile.Errors = make(map[string]error)
+	}
+	profile.Errors[field] = err
+}
+
+// unknownValue tells whether upstream has no data for a field.
+func unknownValue(value string) bool {
+	return value == "" || value == "N/A" || value == "Unknown"
+}
+
+// ParseYearsActive reads periods such as "1988-1991 (as Uruk-Hai), 1991-present".
+func ParseYearsActive(value string) ([]YearsActive, error) {
+	var years []YearsActive
+	if unknownValue(value) {
+		return years, nil
+	}
+
+	// Former names may hold commas too, only commas outside them split.
+	for _, segment := range splitOutsideParens(value) {
+		match := yearsActivere.FindStringSubmatch(segment)
+		if match == nil {
+			return years, fmt.Errorf("Years active segment '%s' has no years.", segment)
+		}
+
+		var period YearsActive
+		period.From, _ = strconv.Atoi(match[1])
+		switch match[2] {
+		case "present":
+			period.Present = true
+		case "":
+			period.To = period.From
+		default:
+			period.To, _ = strconv.Atoi(match[2])
+		}
+		period.Name = strings.TrimSpace(match[3])
+		years = append(years, period)
+	}
+
+	return years, nil
+}
+
+func readProfile(doc *html.Node, profile *ArtistProfile) error {
+	stats := types.FindElement(doc, "div", types.WithID("band_stats"))
+	if stats == nil {
+		return fmt.Errorf("Band page has no stats.")
+	}
+
+	if bandName := types.FindElement(doc, "h1", types.WithClass("band_name")); bandName != nil {
+		profile.Name = types.Text(bandName)
+		if link := types.FindElement(bandName, "a", nil); link != nil {
+			profile.URL = types.Attribute(link, "href")
+		}
+	}
+
+	definitions := types.Definitions(stats)
+	value := func(field string) (*html.Node, bool) {
+		definition, found := definitions[field]
+		if !found {
+			profile.fieldError(field, fmt.Errorf("Band stats have no '%s'.", field))
+		}
+		return definition, found
+	}
+
+	if definition, found := value(CountryField); found {
+		profile.Country = types.Text(definition)
+		if link := types.FindElement(definition, "a", nil); link != nil {
+			if match := countryCodere.FindStringSubmatch(types.Attribute(link, "href")); match != nil {
+				profile.CountryCode = match[1]
+			}
+		}
+	}
+
+	if definition, found := value(LocationField); found {
+		profile.Location = types.Text(definition)
+	}
+
+	if definition, found := value(StatusField); found {
+		status, err := ParseBandStatus(types.Text(definition))
+		if err != nil {
+			profile.fieldError(StatusField, err)
+		}
+		profile.Status = status
+	}
+
+	if definition, found := value(FormedInField); found {
+		if formedIn := types.Text(definition); !unknownValue(formedIn) {
+			year, err := strconv.Atoi(formedIn)
+			if err != nil {
+				profile.fieldError(FormedInField, fmt.Errorf("Formation year '%s' is not a year.", formedIn))
+			}
+			profile.FormedIn = year
+		}
+	}
+
+	if definition, found := value(YearsActiveField); found {
+		years, err := ParseYearsActive(types.Text(definition))
+		if err != nil {
+			profile.fieldError(YearsActiveField, err)
+		}
+		profile.YearsActive = years
+	}
+
+	if definition, found := value(GenreField); found {
+		profile.Genre = types.Text(definition)
+	}
+
+	if definition, found := value(ThemesField); found {
+		profile.Themes = types.Text(definition)
+	}
+
+	// Split-up bands show their last label instead of the current one.
+	label, found := definitions["Current label"]
+	if !found {
+		label, found = definitions["Last label"]
+	}
+	if found {
+		profile.Label = types.Text(label)
+		if link := types.FindElement(label, "a", nil); link != nil {
+			profile.LabelURL = types.Attribute(link, "href")
+		}
+	} else {
+		profile.fieldError(LabelField, fmt.Errorf("Band stats have no label."))
+	}
+
+	if match := lastModifiedre.FindStringSubmatch(types.Text(doc)); match != nil {
+		lastModified, err := time.Parse("2006-01-02 15:04:05", match[1])
+		if err != nil {
+			profile.fieldError(LastModifiedField, err)
+		}
+		profile.LastModified = lastModified
+	} else {
+		profile.fieldError(LastModifiedField, fmt.Errorf("Band page has no last modification date."))
+	}
+
+	if logo := types.FindElement(doc, "a", types.WithID("logo")); logo != nil {
+		profile.LogoURL = types.Attribute(logo, "href")
+	}
+	if photo := types.FindElement(doc, "a", types.WithID("photo")); photo != nil {
+		profile.PhotoURL = types.Attribute(photo, "href")
+	}
+
+	return nil
+}
+
+// GetArtistProfile retrieves the band page of band id. Only pages without
+// band stats fail, fields which cannot be parsed are reported in Errors.
+func GetArtistProfile(ctx context.Context, client scraper.Client, id string) (ArtistProfile, error) {
+	profile := ArtistProfile{ID: id}
+
+	body, err := client.GetWithContext(ctx, scraper.BandEndpoint, fmt.Sprintf("/bands/_/%s", id))
+	if err != nil {
+		return profile, err
+	}
+
+	doc, err := html.Parse(strings.NewReader(string(body)))
+	if err != nil {
+		return profile, &types.ParseError{What: "band page", Err: err}
+	}
+
+	if err := readProfile(doc, &profile); err != nil {
+		return profile, &types.ParseError{What: "band page", Err: err}
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,184 @@
+// +build integration_tests unit_tests
+
+package artists
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const burzumPage = `
+<div id="band_info">
+	<h1 class="band_name"><a href="https://www.metal-archives.com/bands/Burzum/88">Burzum</a></h1>
+	<div class="clear block_spacer_5"></div>
+	<div id="band_stats">
+		<dl class="float_left">
+			<dt>Country of origin:</dt>
+			<dd><a href="https://www.metal-archives.com/lists/NO">Norway</a></dd>
+			<dt>Location:</dt>
+			<dd>Bergen, Vestland</dd>
+			<dt>Status:</dt>
+			<dd class="split_up">Split-up</dd>
+			<dt>Formed in:</dt>
+			<dd>1991</dd>
+		</dl>
+		<dl class="float_right">
+			<dt>Genre:</dt>
+			<dd>Black Metal, Ambient</dd>
+			<dt>Lyrical themes:</dt>
+			<dd>Fantasy, Mythology, Paganism</dd>
+			<dt>Last label:</dt>
+			<dd><a href="https://www.metal-archives.com/labels/Byelobog_Productions/3594">Byelobog Productions</a></dd>
+		</dl>
+		<dl style="width: 100%;" class="clear">
+			<dt>Years active:</dt>
+			<dd>
+				1988-1991
+				(as <a href="https://www.metal-archives.com/bands/Uruk-Hai/1">Uruk-Hai</a>),
+				1991-2018
+			</dd>
+		</dl>
+	</div>
+</div>
+<div class="band_name_img"><a class="image" id="logo" title="Burzum" href="https://www.metal-archives.com/images/8/8/88_logo.jpg?4806"><img src="https://www.metal-archives.com/images/8/8/88_logo.jpg?4806" alt="Burzum logo" /></a></div>
+<div class="band_img"><a class="image" id="photo" title="Burzum" href="https://www.metal-archives.com/images/8/8/88_photo.jpg?2238"><img src="https://www.metal-archives.com/images/8/8/88_photo.jpg?2238" alt="Burzum photo" /></a></div>
+<table id="auditTrail">
+	<tr>
+		<td>Added by: <a href="https://www.metal-archives.com/users/Unknown%20user">Unknown user</a></td>
+		<td align="right">Modified by: <a href="https://www.metal-archives.com/users/someone">someone</a></td>
+	</tr>
+	<tr>
+		<td>Added on: N/A</td>
+		<td align="right">Last modified on: 2024-01-15 12:34:56</td>
+	</tr>
+</table>
+`
+
+func TestGetArtistProfile(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(burzumPage))}}})
+
+	profile, err := GetArtistProfile(context.Background(), client, "88")
+
+	if err != nil {
+		t.Fatalf("GetArtistProfile shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(profile.Errors) != 0 {
+		t.Errorf("Every field should be parsed, errors were '%v'.", profile.Errors)
+	}
+
+	if profile.Name != "Burzum" || profile.ID != "88" || profile.URL != "https://www.metal-archives.com/bands/Burzum/88" {
+		t.Errorf("Profile should be Burzum's one, not '%s' with ID '%s'.", profile.Name, profile.ID)
+	}
+
+	if profile.Country != "Norway" || profile.CountryCode != "NO" || profile.Location != "Bergen, Vestland" {
+		t.Errorf("Burzum should be from Bergen, Vestland, Norway (NO), not '%s', '%s' (%s).", profile.Location, profile.Country, profile.CountryCode)
+	}
+
+	if profile.Status != SplitUpStatus || profile.Status.String() != "Split-up" {
+		t.Errorf("Burzum status should be split-up, not '%s'.", profile.Status)
+	}
+
+	if profile.FormedIn != 1991 {
+		t.Errorf("Burzum should be formed in 1991, not %d.", profile.FormedIn)
+	}
+
+	if len(profile.YearsActive) != 2 {
+		t.Fatalf("Burzum should have two years active periods, not %d.", len(profile.YearsActive))
+	}
+
+	if profile.YearsActive[0] != (YearsActive{From: 1988, To: 1991, Name: "Uruk-Hai"}) {
+		t.Errorf("First period should be 1988-1991 as Uruk-Hai, not '%v'.", profile.YearsActive[0])
+	}
+
+	if profile.YearsActive[1] != (YearsActive{From: 1991, To: 2018}) {
+		t.Errorf("Second period should be 1991-2018, not '%v'.", profile.YearsActive[1])
+	}
+
+	if profile.Genre != "Black Metal, Ambient" || profile.Themes != "Fantasy, Mythology, Paganism" {
+		t.Errorf("Burzum genre and themes are wrong, found '%s' and '%s'.", profile.Genre, profile.Themes)
+	}
+
+	if profile.Label != "Byelobog Productions" || profile.LabelURL != "https://www.metal-archives.com/labels/Byelobog_Productions/3594" {
+		t.Errorf("Burzum last label should be Byelobog Productions, not '%s'.", profile.Label)
+	}
+
+	if !profile.LastModified.Equal(time.Date(2024, 1, 15, 12, 34, 56, 0, time.UTC)) {
+		t.Errorf("Burzum page should be modified on 2024-01-15 12:34:56, not '%s'.", profile.LastModified)
+	}
+
+	if profile.LogoURL != "https://www.metal-archives.com/images/8/8/88_logo.jpg?4806" || profile.PhotoURL != "https://www.metal-archives.com/images/8/8/88_photo.jpg?2238" {
+		t.Errorf("Burzum logo and photo URLs are wrong, found '%s' and '%s'.", profile.LogoURL, profile.PhotoURL)
+	}
+}
+
+func TestGetArtistProfileFieldErrors(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+<h1 class="band_name"><a href="https://www.metal-archives.com/bands/Nameless/1">Nameless</a></h1>
+<div id="band_stats">
+	<dl>
+		<dt>Country of origin:</dt><dd>Unknown</dd>
+		<dt>Status:</dt><dd>Reforming</dd>
+		<dt>Formed in:</dt><dd>nineties</dd>
+		<dt>Years active:</dt><dd>present</dd>
+		<dt>Current label:</dt><dd>Unsigned/independent</dd>
+	</dl>
+</div>
+`))}}})
+
+	profile, err := GetArtistProfile(context.Background(), client, "1")
+
+	if err != nil {
+		t.Fatalf("GetArtistProfile shouldn't fail on broken fields, error was '%s'.", err.Error())
+	}
+
+	for _, field := range []string{StatusField, FormedInField, YearsActiveField, LocationField, GenreField, ThemesField, LastModifiedField} {
+		if profile.Errors[field] == nil {
+			t.Errorf("'%s' field should have an error.", field)
+		}
+	}
+
+	for _, field := range []string{CountryField, LabelField} {
+		if profile.Errors[field] != nil {
+			t.Errorf("'%s' field shouldn't have an error, found '%s'.", field, profile.Errors[field].Error())
+		}
+	}
+
+	if profile.Label != "Unsigned/independent" || profile.LabelURL != "" {
+		t.Errorf("Label should be 'Unsigned/independent' without URL, not '%s'.", profile.Label)
+	}
+}
+
+func TestGetArtistProfileWithoutStats(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`<html><body>Something else</body></html>`))}}})
+
+	_, err := GetArtistProfile(context.Background(), client, "1")
+
+	if !errors.Is(err, types.ErrParse) {
+		t.Errorf("Pages without band stats should return ErrParse, not '%v'.", err)
+	}
+}
+
+func TestParseYearsActiveNameWithComma(t *testing.T) {
+	years, err := ParseYearsActive("1991-1993 (as Foo, Bar), 1994-present")
+
+	if err != nil {
+		t.Fatalf("ParseYearsActive shouldn't fail, error was '%s'.", err.Error())
+	}
+	if len(years) != 2 {
+		t.Fatalf("Years active should have 2 periods, not %d.", len(years))
+	}
+	if years[0].From != 1991 || years[0].To != 1993 || years[0].Name != "Foo, Bar" {
+		t.Errorf("First period should be 1991-1993 as 'Foo, Bar', not '%v'.", years[0])
+	}
+	if years[1].From != 1994 || !years[1].Present || years[1].Name != "" {
+		t.Errorf("Second period should be 1994-present, not '%v'.", years[1])
+	}
+}
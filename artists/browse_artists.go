@@ -0,0 +1,255 @@
+package artists
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+)
+
+// BrowsedArtist is a band listed by upstream browse endpoints. Browsing by
+// country lists Location instead of the band country, which is left empty.
+type BrowsedArtist struct {
+	Artist   SearchArtistData
+	Location string
+	Status   BandStatus
+}
+
+// browseRowReader parses a browse endpoint row.
+type browseRowReader func(row []string) (BrowsedArtist, error)
+
+// readBrowseStatus reads a status cell, unknown statuses are left as 0.
+func readBrowseStatus(cell string) BandStatus {
+	status, _ := ParseBandStatus(types.CellText(cell))
+	return status
+}
+
+// readBandRow parses band, country, genre and status rows, as listed when
+// browsing by letter or genre.
+func readBandRow(row []string) (BrowsedArtist, error) {
+	var band BrowsedArtist
+
+	if len(row) < 4 {
+		return band, fmt.Errorf("Browse row has %d columns instead of 4.", len(row))
+	}
+
+	link, err := types.ReadBandLink(row[0])
+	if err != nil {
+		return band, err
+	}
+	artistData := artistFromLink(link)
+	artistData.Country = types.CellText(row[1])
+	artistData.Genre = types.CellText(row[2])
+	band.Artist = artistData
+	band.Status = readBrowseStatus(row[3])
+
+	return band, nil
+}
+
+// readCountryRow parses band, genre, location and status rows.
+func readCountryRow(row []string) (BrowsedArtist, error) {
+	var band BrowsedArtist
+
+	if len(row) < 4 {
+		return band, fmt.Errorf("Browse row has %d columns instead of 4.", len(row))
+	}
+
+	link, err := types.ReadBandLink(row[0])
+	if err != nil {
+		return band, err
+	}
+	artistData := artistFromLink(link)
+	artistData.Genre = types.CellText(row[1])
+	band.Artist = artistData
+	band.Location = types.CellText(row[2])
+	band.Status = readBrowseStatus(row[3])
+
+	return band, nil
+}
+
+// BandIterator walks every page of a browse endpoint, one band at a time:
+//
+//	bands, _ := BrowseLetter(ctx, client, "A", 0)
+//	for bands.Next() {
+//		band := bands.Band()
+//	}
+//	if bands.Err() != nil {
+//		// Resume later with BrowseLetter(ctx, client, "A", bands.Offset())
+//	}
+type BandIterator struct {
+	ctx     context.Context
+	client  scraper.Client
+	url     string
+	readRow browseRowReader
+
+	offset    int
+	total     int
+	page      []BrowsedArtist
+	pageStart int
+	current   BrowsedArtist
+	err       error
+	done      bool
+}
+
+func newBandIterator(ctx context.Context, client scraper.Client, url string, readRow browseRowReader, offset int) *BandIterator {
+	if offset < 0 {
+		offset = 0
+	}
+	return &BandIterator{ctx: ctx, client: client, url: url, readRow: readRow, offset: offset, total: -1}
+}
+
+// fetch retrieves the page starting at the iterator offset.
+func (iterator *BandIterator) fetch() error {
+	data, err := iterator.client.FetchAjaxPage(iterator.ctx, scraper.BrowseEndpoint, iterator.url, iterator.offset, scraper.AjaxPageLength)
+	if err != nil {
+		return err
+	}
+
+	var page []BrowsedArtist
+	for _, row := range data.Data {
+		band, rowErr := iterator.readRow(row)
+		if rowErr != nil {
+			return &types.ParseError{What: "band browsing", Err: rowErr}
+		}
+		page = append(page, band)
+	}
+
+	iterator.total = data.TotalDisplayRecords
+	iterator.page = page
+	iterator.pageStart = iterator.offset
+	return nil
+}
+
+// Next advances to the next band, it returns false when every band has been
+// walked or a page could not be retrieved, see Err.
+func (iterator *BandIterator) Next() bool {
+	if iterator.done || iterator.err != nil {
+		return false
+	}
+
+	position := iterator.offset - iterator.pageStart
+	if iterator.page == nil || position >= len(iterator.page) {
+		if iterator.page != nil && len(iterator.page) < scraper.AjaxPageLength {
+			iterator.done = true
+			return false
+		}
+		if iterator.total >= 0 && iterator.offset >= iterator.total {
+			iterator.done = true
+			return false
+		}
+		if err := iterator.fetch(); err != nil {
+			iterator.err = err
+			iterator.page = nil
+			return false
+		}
+		position = 0
+		if len(iterator.page) == 0 {
+			iterator.done = true
+			return false
+		}
+	}
+
+	iterator.current = iterator.page[position]
+	iterator.offset++
+	return true
+}
+
+// Band returns the band Next advanced to.
+func (iterator *BandIterator) Band() BrowsedArtist {
+	return iterator.current
+}
+
+// Offset is the amount of bands walked, browsing again from it resumes
+// after the last band returned.
+func (iterator *BandIterator) Offset() int {
+	return iterator.offset
+}
+
+// Total is the amount of bands upstream reported, -1 until the first page
+// is retrieved.
+func (iterator *BandIterator) Total() int {
+	return iterator.total
+}
+
+// Err returns the error which stopped the iterator.
+func (iterator *BandIterator) Err() error {
+	return iterator.err
+}
+
+// All walks every band left, bands walked before an error are returned with it.
+func (iterator *BandIterator) All() ([]BrowsedArtist, error) {
+	var bands []BrowsedArtist
+	for iterator.Next() {
+		bands = append(bands, iterator.Band())
+	}
+	return bands, iterator.Err()
+}
+
+// validLetter tells whether letter is one of the letters upstream lists
+// bands by: A to Z, "NBR" for bands starting with a number and "~" for
+// bands starting with other characters.
+func validLetter(letter string) bool {
+	if letter == "NBR" || letter == "~" {
+		return true
+	}
+	return len(letter) == 1 && letter[0] >= 'A' && letter[0] <= 'Z'
+}
+
+// BrowseLetter walks bands whose name starts with letter from offset.
+func BrowseLetter(ctx context.Context, client scraper.Client, letter string, offset int) (*BandIterator, error) {
+	letter = strings.ToUpper(letter)
+	if !validLetter(letter) {
+		return nil, fmt.Errorf("'%s' is not a valid browsing letter.", letter)
+	}
+
+	url := fmt.Sprintf("/browse/ajax-letter/l/%s/json/1", letter)
+	return newBandIterator(ctx, client, url, readBandRow, offset), nil
+}
+
+var (
+	browseCountryre = regexp.MustCompile(`^[A-Z]{2}$`)
+	browseGenrere   = regexp.MustCompile(`^[a-z]+$`)
+)
+
+// BrowseCountry walks bands from the country with ISO 3166 code from offset.
+func BrowseCountry(ctx context.Context, client scraper.Client, code string, offset int) (*BandIterator, error) {
+	code = strings.ToUpper(code)
+	if !browseCountryre.MatchString(code) {
+		return nil, fmt.Errorf("'%s' is not a valid country code.", code)
+	}
+
+	url := fmt.Sprintf("/browse/ajax-country/c/%s/json/1", code)
+	return newBandIterator(ctx, client, url, readCountryRow, offset), nil
+}
+
+// BrowseGenre walks bands of the genre with upstream slug, such as "black"
+// or "prog", from offset.
+func BrowseGenre(ctx context.Context, client scraper.Client, slug string, offset int) (*BandIterator, error) {
+	if !browseGenrere.MatchString(slug) {
+		return nil, fmt.Errorf("'%s' is not a valid genre slug.", slug)
+	}
+
+	url := fmt.Sprintf("/browse/ajax-genre/g/%s/json/1", slug)
+	return newBandIterator(ctx, client, url, readBandRow, offset), nil
+}
+
+// GetCountryArtists returns every band from the country with ISO 3166 code.
+func GetCountryArtists(ctx context.Context, client scraper.Client, code string) ([]BrowsedArtist, error) {
+	bands, err := BrowseCountry(ctx, client, code, 0)
+	if err != nil {
+		return nil, err
+	}
+	return bands.All()
+}
+
+// GetGenreArtists returns every band of the genre with upstream slug.
+func GetGenreArtists(ctx context.Context, client scraper.Client, slug string) ([]BrowsedArtist, error) {
+	bands, err := BrowseGenre(ctx, client, slug, 0)
+	if err != nil {
+		return nil, err
+	}
+	return bands.All()
+}
@@ -0,0 +1,202 @@
+// +build integration_tests unit_tests
+
+package artists
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newBrowseServer serves totalRecords letter rows, failing the request
+// starting at failAt when it is not negative.
+func newBrowseServer(totalRecords int, failAt *int, paths *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*paths = append(*paths, r.URL.Path)
+		start, _ := strconv.Atoi(r.URL.Query().Get("iDisplayStart"))
+		length, _ := strconv.Atoi(r.URL.Query().Get("iDisplayLength"))
+
+		if start == *failAt {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		page := types.SearchAjaxData{TotalRecords: totalRecords, TotalDisplayRecords: totalRecords, Data: [][]string{}}
+		for row := start; row < start+length && row < totalRecords; row++ {
+			page.Data = append(page.Data, []string{
+				fmt.Sprintf(`<a href='https://www.metal-archives.com/bands/A_%d/%d'>A &amp; %d</a>`, row, row, row),
+				"Norway",
+				"Black Metal",
+				`<span class="split_up">Split-up</span>`,
+			})
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+}
+
+func TestBrowseLetter(t *testing.T) {
+	failAt := -1
+	var paths []string
+	server := newBrowseServer(450, &failAt, &paths)
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	bands, err := BrowseLetter(context.Background(), client, "a", 0)
+	if err != nil {
+		t.Fatalf("BrowseLetter shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	count := 0
+	for bands.Next() {
+		band := bands.Band()
+		if band.Artist.ID != strconv.Itoa(count) {
+			t.Fatalf("Band %d should have ID %d, not '%s'.", count, count, band.Artist.ID)
+		}
+		count++
+	}
+
+	if bands.Err() != nil {
+		t.Fatalf("Browsing shouldn't fail, error was '%s'.", bands.Err().Error())
+	}
+
+	if count != 450 || bands.Total() != 450 || bands.Offset() != 450 {
+		t.Errorf("Every band should be walked, walked %d of %d.", count, bands.Total())
+	}
+
+	if len(paths) != 3 || paths[0] != "/browse/ajax-letter/l/A/json/1" {
+		t.Errorf("Three pages of letter A should be requested, requested '%v'.", paths)
+	}
+
+	bands, _ = BrowseLetter(context.Background(), client, "A", 0)
+	bands.Next()
+	band := bands.Band()
+	if band.Artist.Name != "A & 0" || band.Artist.URL != "https://www.metal-archives.com/bands/A_0/0" || band.Artist.Country != "Norway" || band.Artist.Genre != "Black Metal" || band.Status != SplitUpStatus {
+		t.Errorf("First band is wrong, found '%v'.", band)
+	}
+}
+
+func TestBrowseLetterResume(t *testing.T) {
+	failAt := 200
+	var paths []string
+	server := newBrowseServer(450, &failAt, &paths)
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	bands, _ := BrowseLetter(context.Background(), client, "NBR", 0)
+	count := 0
+	for bands.Next() {
+		count++
+	}
+
+	if !errors.Is(bands.Err(), types.ErrNotFound) || count != 200 || bands.Offset() != 200 {
+		t.Fatalf("Browsing should stop at band 200 with ErrNotFound, stopped at %d with '%v'.", bands.Offset(), bands.Err())
+	}
+
+	failAt = -1
+	bands, _ = BrowseLetter(context.Background(), client, "NBR", bands.Offset())
+	for bands.Next() {
+		if count == 200 && bands.Band().Artist.ID != "200" {
+			t.Errorf("Resumed browsing should start at band 200, not '%s'.", bands.Band().Artist.ID)
+		}
+		count++
+	}
+
+	if bands.Err() != nil || count != 450 {
+		t.Errorf("Resumed browsing should walk every band left, walked %d with '%v'.", count, bands.Err())
+	}
+}
+
+func TestBrowseLetterInvalid(t *testing.T) {
+	client := scraper.NewClient(http.Client{})
+
+	for _, letter := range []string{"", "AB", "1", "Ñ"} {
+		if _, err := BrowseLetter(context.Background(), client, letter, 0); err == nil {
+			t.Errorf("'%s' shouldn't be a valid browsing letter.", letter)
+		}
+	}
+}
+
+func TestGetCountryArtists(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Path
+		start, _ := strconv.Atoi(r.URL.Query().Get("iDisplayStart"))
+		length, _ := strconv.Atoi(r.URL.Query().Get("iDisplayLength"))
+
+		page := types.SearchAjaxData{TotalRecords: 250, TotalDisplayRecords: 250, Data: [][]string{}}
+		for row := start; row < start+length && row < 250; row++ {
+			page.Data = append(page.Data, []string{
+				fmt.Sprintf(`<a href='https://www.metal-archives.com/bands/Band_%d/%d'>Band %d</a>`, row, row, row),
+				"Black Metal",
+				"Bergen, Vestland",
+				`<span class="active">Active</span>`,
+			})
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	bands, err := GetCountryArtists(context.Background(), client, "no")
+
+	if err != nil {
+		t.Fatalf("GetCountryArtists shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if query != "/browse/ajax-country/c/NO/json/1" {
+		t.Errorf("Norway listing should be requested, not '%s'.", query)
+	}
+
+	if len(bands) != 250 {
+		t.Fatalf("Every band should be returned, returned %d.", len(bands))
+	}
+
+	if bands[249].Artist.ID != "249" || bands[249].Artist.Genre != "Black Metal" || bands[249].Location != "Bergen, Vestland" || bands[249].Status != ActiveStatus || bands[249].Artist.Country != "" {
+		t.Errorf("Last band is wrong, found '%v'.", bands[249])
+	}
+
+	if _, err := GetCountryArtists(context.Background(), client, "Norway"); err == nil {
+		t.Errorf("'Norway' shouldn't be a valid country code.")
+	}
+}
+
+func TestGetGenreArtists(t *testing.T) {
+	failAt := -1
+	var paths []string
+	server := newBrowseServer(10, &failAt, &paths)
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	bands, err := GetGenreArtists(context.Background(), client, "black")
+
+	if err != nil {
+		t.Fatalf("GetGenreArtists shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(paths) != 1 || paths[0] != "/browse/ajax-genre/g/black/json/1" {
+		t.Errorf("Black metal listing should be requested once, requested '%v'.", paths)
+	}
+
+	if len(bands) != 10 || bands[0].Artist.Country != "Norway" || bands[0].Artist.Genre != "Black Metal" {
+		t.Errorf("Every band should be returned with its country and genre, found '%v'.", bands)
+	}
+
+	if _, err := GetGenreArtists(context.Background(), client, "black metal"); err == nil {
+		t.Errorf("'black metal' shouldn't be a valid genre slug.")
+	}
+}
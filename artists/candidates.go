@@ -0,0 +1,82 @@
+package artists
+
+import (
+	"context"
+	"sort"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+)
+
+// ArtistCandidate is a band found searching an artist name. Score is the
+// upstream relevance, Similarity how close the band name or one of its
+// aliases is to the searched one from 0 to 1.
+type ArtistCandidate struct {
+	Artist     SearchArtistData
+	Score      float64
+	Similarity float64
+	Rank       int
+	Exact      bool
+}
+
+// Matched tells whether candidate name or aliases match the searched name.
+func (candidate ArtistCandidate) Matched() bool {
+	return candidate.Rank != types.NoMatchRank
+}
+
+// GetArtistCandidates returns every band found searching artist, matching
+// bands first by rank and then by similarity, ties keep upstream order.
+func GetArtistCandidates(ctx context.Context, client scraper.Client, artist string) ([]ArtistCandidate, error) {
+	var candidates []ArtistCandidate
+
+	data, err := searchArtistAjax(ctx, client, artist)
+	if err != nil {
+		return candidates, err
+	}
+
+	for _, row := range data.Data {
+		artistData, rowErr := readSearchArtistRow(row)
+		if rowErr != nil {
+			return candidates, rowErr
+		}
+
+		candidate := ArtistCandidate{Artist: artistData, Score: types.ReadScore(row[0])}
+		candidate.Rank = artistMatchRank(artist, artistData, client.MatchMode)
+		candidate.Exact = candidate.Rank == types.ExactMatchRank
+		candidate.Similarity = types.Similarity(artist, artistData.Name)
+		for _, alias := range artistData.Aliases {
+			if similarity := types.Similarity(artist, alias); similarity > candidate.Similarity {
+				candidate.Similarity = similarity
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Rank != candidates[j].Rank {
+			return candidates[i].Rank < candidates[j].Rank
+		}
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+
+	return candidates, nil
+}
+
+// SelectArtistMatches splits candidates as SearchArtist does, the best
+// matching band and the other matching ones.
+func SelectArtistMatches(candidates []ArtistCandidate) (SearchArtistData, []SearchArtistData, error) {
+	var artistData SearchArtistData
+	var artistExtraData []SearchArtistData
+
+	for _, candidate := range candidates {
+		if candidate.Matched() {
+			artistExtraData = append(artistExtraData, candidate.Artist)
+		}
+	}
+
+	if len(artistExtraData) == 0 {
+		return artistData, nil, &types.NoMatchError{Message: "No artist was found."}
+	}
+
+	return artistExtraData[0], artistExtraData[1:], nil
+}
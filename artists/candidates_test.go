@@ -0,0 +1,85 @@
+// +build integration_tests unit_tests
+
+package artists
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+const burzumSearch = `
+{
+	"error": "",
+	"iTotalRecords": 3,
+	"iTotalDisplayRecords": 3,
+	"sEcho": 0,
+	"aaData": [
+				[
+			"<a href=\"https://www.metal-archives.com/bands/Down_to_Burzum/3540435931\">Down to Burzum</a>  <!-- 5.716357 -->" ,
+			"Black Metal" ,
+			"Brazil"     		]
+				,
+						[
+			"<a href=\"https://www.metal-archives.com/bands/Burzum/88\">Burzum</a>  <!-- 11.432714 -->" ,
+			"Black Metal, Ambient" ,
+			"Norway"     		]
+				,
+						[
+			"<a href=\"https://www.metal-archives.com/bands/Krimparturr/21151\">Krimparturr</a> (<strong>a.k.a.</strong> Krimpartûrr Bürzum Shi-Hai) <!-- 1.2505064 -->" ,
+			"Black Metal" ,
+			"Brazil"     		]
+				]
+}
+`
+
+func TestGetArtistCandidates(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(burzumSearch))}}})
+
+	candidates, err := GetArtistCandidates(context.Background(), client, "Burzum")
+
+	if err != nil {
+		t.Fatalf("GetArtistCandidates shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(candidates) != 3 {
+		t.Fatalf("GetArtistCandidates should return every band found, it returned %d.", len(candidates))
+	}
+
+	if candidates[0].Artist.ID != "88" || !candidates[0].Exact || candidates[0].Score != 11.432714 || candidates[0].Similarity != 1 {
+		t.Errorf("Exact match 'Burzum' should be the first candidate with upstream score and similarity 1, not '%v'.", candidates[0])
+	}
+
+	if candidates[1].Artist.ID != "3540435931" || candidates[1].Exact || candidates[1].Matched() || candidates[1].Score != 5.716357 {
+		t.Errorf("'Down to Burzum' should be the second candidate and it doesn't match, not '%v'.", candidates[1])
+	}
+
+	if candidates[1].Similarity <= candidates[2].Similarity {
+		t.Errorf("'Down to Burzum' should be more similar to 'Burzum' than 'Krimparturr'.")
+	}
+}
+
+func TestSelectArtistMatchesNoMatch(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(burzumSearch))}}})
+
+	candidates, err := GetArtistCandidates(context.Background(), client, "Burzun")
+
+	if err != nil {
+		t.Fatalf("GetArtistCandidates shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(candidates) != 3 || candidates[0].Artist.Name != "Burzum" {
+		t.Errorf("Closest candidate to 'Burzun' should be 'Burzum'.")
+	}
+
+	_, _, err = SelectArtistMatches(candidates)
+
+	if !errors.Is(err, types.ErrNoMatch) {
+		t.Errorf("SelectArtistMatches should return ErrNoMatch when no candidate matches, not '%v'.", err)
+	}
+}
@@ -0,0 +1,193 @@
+package artists
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+)
+
+// Hint names reported in Disambiguation MatchedHints.
+const (
+	CountryHint = "country"
+	GenreHint   = "genre"
+	AlbumHint   = "album"
+	YearHint    = "year"
+)
+
+// Disambiguation is the band chosen among the ones matching a searched name.
+// Confidence goes from 1/len(matches) when hints do not tell bands apart to
+// 1 when only the chosen band fits every hint. When only one band matches it
+// is the share of hints the band fits, or 1 without hints.
+type Disambiguation struct {
+	Artist       SearchArtistData
+	ExtraData    []SearchArtistData
+	MatchedHints []string
+	Confidence   float64
+}
+
+type hintScore struct {
+	artist  SearchArtistData
+	score   float64
+	matched []string
+}
+
+// hintCount is how many hints can be scored, the maximum score of a band.
+func hintCount(hints types.ArtistHints) int {
+	count := 0
+	for _, set := range []bool{hints.Country != "", len(hints.Genres) > 0, hints.Album != "", hints.Year > 0} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+func scoreHints(ctx context.Context, client scraper.Client, artistData SearchArtistData, hints types.ArtistHints) (hintScore, error) {
+	scored := hintScore{artist: artistData}
+
+	if hints.Country != "" && strings.EqualFold(hints.Country, artistData.Country) {
+		scored.score++
+		scored.matched = append(scored.matched, CountryHint)
+	}
+
+	if len(hints.Genres) > 0 {
+		genre := strings.ToLower(artistData.Genre)
+		found := 0
+		for _, keyword := range hints.Genres {
+			if strings.Contains(genre, strings.ToLower(keyword)) {
+				found++
+			}
+		}
+		if found > 0 {
+			scored.score += float64(found) / float64(len(hints.Genres))
+			scored.matched = append(scored.matched, GenreHint)
+		}
+	}
+
+	if hints.Album == "" && hints.Year == 0 {
+		return scored, nil
+	}
+
+	records, err := GetArtistRecordsWithContext(ctx, client, artistData)
+	if err != nil && !errors.Is(err, types.ErrNoMatch) {
+		return scored, err
+	}
+
+	if hints.Album != "" && hasRecord(records, hints.Album, client.MatchMode) {
+		scored.score++
+		scored.matched = append(scored.matched, AlbumHint)
+	}
+
+	if hints.Year > 0 && activeIn(records, hints.Year) {
+		scored.score++
+		scored.matched = append(scored.matched, YearHint)
+	}
+
+	return scored, nil
+}
+
+func hasRecord(records []commontypes.Record, album string, mode types.MatchMode) bool {
+	for _, record := range records {
+		if types.MatchRank(album, record.Name, mode) != types.NoMatchRank {
+			return true
+		}
+	}
+	return false
+}
+
+// activeIn tells whether year falls between the first and last release years.
+func activeIn(records []commontypes.Record, year int) bool {
+	first, last := 0, 0
+	for _, record := range records {
+		if record.Year == 0 {
+			continue
+		}
+		if first == 0 || record.Year < first {
+			first = record.Year
+		}
+		if record.Year > last {
+			last = record.Year
+		}
+	}
+	return first != 0 && year >= first && year <= last
+}
+
+// DisambiguateArtist searches artist and chooses among the matching bands the
+// one fitting hints best, ties keep SearchArtist order. Album and year hints
+// need each matching band discography.
+func DisambiguateArtist(ctx context.Context, client scraper.Client, artist string, hints types.ArtistHints) (Disambiguation, error) {
+	candidates, err := GetArtistCandidates(ctx, client, artist)
+	if err != nil {
+		return Disambiguation{}, err
+	}
+
+	return DisambiguateCandidates(ctx, client, candidates, hints)
+}
+
+// DisambiguateCandidates chooses among matching candidates as
+// DisambiguateArtist does.
+func DisambiguateCandidates(ctx context.Context, client scraper.Client, candidates []ArtistCandidate, hints types.ArtistHints) (Disambiguation, error) {
+	var disambiguation Disambiguation
+
+	artistData, extraData, err := SelectArtistMatches(candidates)
+	if err != nil {
+		return disambiguation, err
+	}
+
+	matches := append([]SearchArtistData{artistData}, extraData...)
+	if hintCount(hints) == 0 {
+		disambiguation.Artist = artistData
+		disambiguation.ExtraData = extraData
+		disambiguation.Confidence = 1 / float64(len(matches))
+		return disambiguation, nil
+	}
+
+	// A single match is still returned, hints only tell how much they support it.
+	if len(matches) == 1 {
+		scored, scoreErr := scoreHints(ctx, client, artistData, hints)
+		if scoreErr != nil {
+			return disambiguation, scoreErr
+		}
+		disambiguation.Artist = artistData
+		disambiguation.MatchedHints = scored.matched
+		disambiguation.Confidence = scored.score / float64(hintCount(hints))
+		return disambiguation, nil
+	}
+
+	var scores []hintScore
+	best := 0
+	for i, match := range matches {
+		scored, scoreErr := scoreHints(ctx, client, match, hints)
+		if scoreErr != nil {
+			return disambiguation, scoreErr
+		}
+		scores = append(scores, scored)
+		if scored.score > scores[best].score {
+			best = i
+		}
+	}
+
+	runnerUp := 0.0
+	for i, scored := range scores {
+		if i != best && scored.score > runnerUp {
+			runnerUp = scored.score
+		}
+	}
+
+	disambiguation.Artist = scores[best].artist
+	disambiguation.MatchedHints = scores[best].matched
+	for i, scored := range scores {
+		if i != best {
+			disambiguation.ExtraData = append(disambiguation.ExtraData, scored.artist)
+		}
+	}
+
+	uniform := 1 / float64(len(matches))
+	disambiguation.Confidence = uniform + (1-uniform)*(scores[best].score-runnerUp)/float64(hintCount(hints))
+
+	return disambiguation, nil
+}
@@ -0,0 +1,172 @@
+// +build integration_tests unit_tests
+
+package artists
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const sacrificeSearch = `
+{
+	"error": "",
+	"iTotalRecords": 3,
+	"iTotalDisplayRecords": 3,
+	"sEcho": 0,
+	"aaData": [
+				[
+			"<a href=\"https://www.metal-archives.com/bands/Sacrifice/1\">Sacrifice</a>  <!-- 11.3 -->" ,
+			"Thrash Metal" ,
+			"Canada"     		]
+				,
+						[
+			"<a href=\"https://www.metal-archives.com/bands/Sacrifice/2\">Sacrifice</a>  <!-- 11.3 -->" ,
+			"Black/Thrash Metal" ,
+			"Japan"     		]
+				,
+						[
+			"<a href=\"https://www.metal-archives.com/bands/Sacrifice/3\">Sacrifice</a>  <!-- 11.3 -->" ,
+			"Death Metal" ,
+			"Sweden"     		]
+				]
+}
+`
+
+func sacrificeDiscography(records ...string) string {
+	var rows strings.Builder
+	for _, record := range records {
+		fields := strings.Split(record, "|")
+		rows.WriteString(fmt.Sprintf(`<tr><td><a href="https://www.metal-archives.com/albums/Sacrifice/%s/%s">%s</a></td><td>Full-length</td><td>%s</td></tr>`, fields[0], fields[2], fields[0], fields[1]))
+	}
+	return `<table class="display discog"><tbody>` + rows.String() + `</tbody></table>`
+}
+
+func TestDisambiguateArtistCountryAndGenre(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(sacrificeSearch))}}})
+
+	disambiguation, err := DisambiguateArtist(context.Background(), client, "Sacrifice", types.ArtistHints{Country: "japan", Genres: []string{"black"}})
+
+	if err != nil {
+		t.Fatalf("DisambiguateArtist shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if disambiguation.Artist.ID != "2" {
+		t.Errorf("Japanese black/thrash band should be chosen, not '%s'.", disambiguation.Artist.ID)
+	}
+
+	if len(disambiguation.ExtraData) != 2 || disambiguation.ExtraData[0].ID != "1" {
+		t.Errorf("Other bands should be returned as extra data keeping search order.")
+	}
+
+	if strings.Join(disambiguation.MatchedHints, ",") != "country,genre" {
+		t.Errorf("Matched hints should be 'country,genre', not '%v'.", disambiguation.MatchedHints)
+	}
+
+	if disambiguation.Confidence != 1 {
+		t.Errorf("Confidence should be 1 when only the chosen band fits every hint, not %f.", disambiguation.Confidence)
+	}
+}
+
+func TestDisambiguateArtistWithoutHints(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(sacrificeSearch))}}})
+
+	disambiguation, err := DisambiguateArtist(context.Background(), client, "Sacrifice", types.ArtistHints{})
+
+	if err != nil {
+		t.Fatalf("DisambiguateArtist shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if disambiguation.Artist.ID != "1" || len(disambiguation.MatchedHints) != 0 {
+		t.Errorf("First band should be chosen when there are no hints, not '%s'.", disambiguation.Artist.ID)
+	}
+
+	if disambiguation.Confidence != 1.0/3 {
+		t.Errorf("Confidence should be 1/3 when there are no hints, not %f.", disambiguation.Confidence)
+	}
+}
+
+const canadianSacrificeSearch = `
+{
+	"iTotalRecords": 1,
+	"iTotalDisplayRecords": 1,
+	"aaData": [
+		[
+			"<a href=\"https://www.metal-archives.com/bands/Sacrifice/1\">Sacrifice</a>  <!-- 11.3 -->" ,
+			"Thrash Metal" ,
+			"Canada"		]
+	]
+}
+`
+
+func TestDisambiguateArtistSingleMatchUnsupported(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(canadianSacrificeSearch))}}})
+
+	disambiguation, err := DisambiguateArtist(context.Background(), client, "Sacrifice", types.ArtistHints{Country: "Sweden"})
+
+	if err != nil {
+		t.Fatalf("DisambiguateArtist shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if disambiguation.Artist.ID != "1" {
+		t.Errorf("The only matching band should be returned, not '%s'.", disambiguation.Artist.ID)
+	}
+
+	if len(disambiguation.MatchedHints) != 0 || disambiguation.Confidence != 0 {
+		t.Errorf("Swedish hint shouldn't support a Canadian band, found confidence %f with '%v'.", disambiguation.Confidence, disambiguation.MatchedHints)
+	}
+}
+
+func TestDisambiguateArtistSingleMatchPartlySupported(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(canadianSacrificeSearch))}}})
+
+	disambiguation, err := DisambiguateArtist(context.Background(), client, "Sacrifice", types.ArtistHints{Country: "Canada", Genres: []string{"death"}})
+
+	if err != nil {
+		t.Fatalf("DisambiguateArtist shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if strings.Join(disambiguation.MatchedHints, ",") != "country" || disambiguation.Confidence != 0.5 {
+		t.Errorf("Only the country hint should support the band with confidence 0.5, found %f with '%v'.", disambiguation.Confidence, disambiguation.MatchedHints)
+	}
+}
+
+func TestDisambiguateArtistAlbumAndYear(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/band/discography/id/1/tab/all":
+			fmt.Fprint(w, sacrificeDiscography("Torment in Fire|1985|10", "Forward to Termination|1987|11"))
+		case "/band/discography/id/2/tab/all":
+			fmt.Fprint(w, sacrificeDiscography("Sacrifice|1993|20"))
+		case "/band/discography/id/3/tab/all":
+			fmt.Fprint(w, `<table class="display discog"><tbody><tr><td colspan="4"><em>Nothing entered yet.</em></td></tr></tbody></table>`)
+		default:
+			fmt.Fprint(w, sacrificeSearch)
+		}
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	disambiguation, err := DisambiguateArtist(context.Background(), client, "Sacrifice", types.ArtistHints{Album: "Forward to Termination", Year: 1986})
+
+	if err != nil {
+		t.Fatalf("DisambiguateArtist shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if disambiguation.Artist.ID != "1" {
+		t.Errorf("Canadian band should be chosen by its album, not '%s'.", disambiguation.Artist.ID)
+	}
+
+	if strings.Join(disambiguation.MatchedHints, ",") != "album,year" {
+		t.Errorf("Matched hints should be 'album,year', not '%v'.", disambiguation.MatchedHints)
+	}
+}
@@ -0,0 +1,105 @@
+package artists
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+)
+
+// BandListing is one of the upstream latest bands listings.
+type BandListing string
+
+const (
+	AddedBands   BandListing = "created"
+	UpdatedBands BandListing = "modified"
+)
+
+// BandChange is a band added or updated by User at Date.
+type BandChange struct {
+	Artist SearchArtistData
+	types.Change
+}
+
+// readBandChangeRow parses day, band, country, genre, timestamp and user rows.
+func readBandChangeRow(row []string) (BandChange, error) {
+	var band BandChange
+
+	if len(row) < 6 {
+		return band, fmt.Errorf("Latest bands row has %d columns instead of 6.", len(row))
+	}
+
+	link, err := types.ReadBandLink(row[1])
+	if err != nil {
+		return band, err
+	}
+	artistData := artistFromLink(link)
+	artistData.Country = types.CellText(row[2])
+	artistData.Genre = types.CellText(row[3])
+	band.Artist = artistData
+
+	change, err := types.ReadChange(row[4], row[5])
+	if err != nil {
+		return band, err
+	}
+	band.Change = change
+
+	return band, nil
+}
+
+// GetLatestArtists returns every band added or updated, depending on listing,
+// during month.
+func GetLatestArtists(ctx context.Context, client scraper.Client, listing BandListing, month time.Time) ([]BandChange, error) {
+	var bands []BandChange
+
+	if listing != AddedBands && listing != UpdatedBands {
+		return bands, fmt.Errorf("'%s' is not a latest bands listing.", listing)
+	}
+
+	url := fmt.Sprintf("/archives/ajax-band-list/selection/%s/by/%s//json/1", types.ChangeMonth(month), listing)
+	data, err := client.FetchAjaxPages(ctx, scraper.LatestEndpoint, url, 0, 0)
+	if err != nil {
+		return bands, err
+	}
+
+	for _, row := range data.Data {
+		band, rowErr := readBandChangeRow(row)
+		if rowErr != nil {
+			return bands, &types.ParseError{What: "latest bands", Err: rowErr}
+		}
+		bands = append(bands, band)
+	}
+
+	return bands, nil
+}
+
+// GetArtistChangesSince returns bands added or updated, depending on listing,
+// after since, newest first. Every month from since to the current one is
+// retrieved, sync jobs polling often only need the last one or two.
+func GetArtistChangesSince(ctx context.Context, client scraper.Client, listing BandListing, since time.Time) ([]BandChange, error) {
+	var changes []BandChange
+
+	since = since.UTC()
+	first := time.Date(since.Year(), since.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for month := time.Now().UTC(); !month.Before(first); month = month.AddDate(0, -1, 0) {
+		month = time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+		bands, err := GetLatestArtists(ctx, client, listing, month)
+		if err != nil {
+			return changes, err
+		}
+		for _, band := range bands {
+			if band.Date.After(since) {
+				changes = append(changes, band)
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Date.After(changes[j].Date)
+	})
+
+	return changes, nil
+}
@@ -0,0 +1,130 @@
+// +build integration_tests unit_tests
+
+package artists
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newLatestServer serves the given timestamps as latest bands rows of each
+// month selection, the band ID is the row position within its month.
+func newLatestServer(months map[string][]string, paths *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*paths = append(*paths, r.URL.Path)
+
+		page := types.SearchAjaxData{Data: [][]string{}}
+		for month, dates := range months {
+			if r.URL.Path != fmt.Sprintf("/archives/ajax-band-list/selection/%s/by/modified//json/1", month) {
+				continue
+			}
+			for i, date := range dates {
+				page.Data = append(page.Data, []string{
+					"May 31",
+					fmt.Sprintf(`<a href="https://www.metal-archives.com/bands/Band_%s/%d">Band %s %d</a>`, month, i, month, i),
+					`<a href="https://www.metal-archives.com/lists/NO">Norway</a>`,
+					"Black Metal",
+					date,
+					`<a href="https://www.metal-archives.com/users/Someone" class="profileMenu">Someone</a>`,
+				})
+			}
+		}
+		page.TotalRecords = len(page.Data)
+		page.TotalDisplayRecords = len(page.Data)
+		json.NewEncoder(w).Encode(page)
+	}))
+}
+
+func TestGetLatestArtists(t *testing.T) {
+	var paths []string
+	server := newLatestServer(map[string][]string{"2024-05": {"2024-05-31 21:47:23", "2024-05-30 10:00:00"}}, &paths)
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	bands, err := GetLatestArtists(context.Background(), client, UpdatedBands, time.Date(2024, time.May, 12, 0, 0, 0, 0, time.UTC))
+
+	if err != nil {
+		t.Fatalf("GetLatestArtists shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(bands) != 2 {
+		t.Fatalf("Every updated band should be returned, returned %d.", len(bands))
+	}
+
+	if bands[0].Artist.ID != "0" || bands[0].Artist.Country != "Norway" || bands[0].Artist.Genre != "Black Metal" {
+		t.Errorf("First band is wrong, found '%v'.", bands[0].Artist)
+	}
+
+	if !bands[0].Date.Equal(time.Date(2024, time.May, 31, 21, 47, 23, 0, time.UTC)) || bands[0].User != "Someone" {
+		t.Errorf("First band should be updated by Someone on 2024-05-31 21:47:23, not by '%s' on '%s'.", bands[0].User, bands[0].Date)
+	}
+}
+
+func TestGetLatestArtistsInvalidListing(t *testing.T) {
+	client := scraper.NewClient(http.Client{})
+
+	if _, err := GetLatestArtists(context.Background(), client, BandListing("deleted"), time.Now()); err == nil {
+		t.Errorf("'deleted' shouldn't be a valid listing.")
+	}
+}
+
+func TestGetLatestArtistsBrokenRow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"iTotalRecords": 1, "iTotalDisplayRecords": 1, "aaData": [["May 31", "<a href=\"https://www.metal-archives.com/bands/Band/1\">Band</a>", "Norway", "Black Metal", "yesterday", "Someone"]]}`))
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	_, err := GetLatestArtists(context.Background(), client, AddedBands, time.Now())
+
+	var parseErr *types.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("Rows without timestamp should fail with a parse error, not '%v'.", err)
+	}
+}
+
+func TestGetArtistChangesSince(t *testing.T) {
+	now := time.Now().UTC()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	previous := current.AddDate(0, -1, 0)
+
+	var paths []string
+	server := newLatestServer(map[string][]string{
+		types.ChangeMonth(current):  {current.Format(types.ChangeTimeLayout)},
+		types.ChangeMonth(previous): {previous.AddDate(0, 0, 19).Format(types.ChangeTimeLayout), previous.AddDate(0, 0, 4).Format(types.ChangeTimeLayout)},
+	}, &paths)
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	changes, err := GetArtistChangesSince(context.Background(), client, UpdatedBands, previous.AddDate(0, 0, 9))
+
+	if err != nil {
+		t.Fatalf("GetArtistChangesSince shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(paths) != 2 {
+		t.Errorf("Current and previous months should be requested, requested '%v'.", paths)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("Only changes after since should be returned, returned %d.", len(changes))
+	}
+
+	if !changes[0].Date.Equal(current) || !changes[1].Date.Equal(previous.AddDate(0, 0, 19)) {
+		t.Errorf("Changes should be sorted newest first, found '%s' and '%s'.", changes[0].Date, changes[1].Date)
+	}
+}
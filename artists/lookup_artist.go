@@ -0,0 +1,63 @@
+package artists
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+)
+
+var artistIDre = regexp.MustCompile(`^[0-9]+$`)
+
+// ParseArtistID returns the band ID of idOrURL, which is either a band ID or
+// a band page URL.
+func ParseArtistID(idOrURL string) (string, error) {
+	if artistIDre.MatchString(idOrURL) {
+		return idOrURL, nil
+	}
+	if id, found := types.BandID(idOrURL); found {
+		return id, nil
+	}
+	return "", fmt.Errorf("'%s' is neither a band ID nor a band URL.", idOrURL)
+}
+
+// LookupArtist retrieves the band idOrURL points to from its band page,
+// without searching it by name.
+func LookupArtist(ctx context.Context, client scraper.Client, idOrURL string) (SearchArtistData, error) {
+	var artistData SearchArtistData
+
+	id, err := ParseArtistID(idOrURL)
+	if err != nil {
+		return artistData, err
+	}
+
+	profile, err := GetArtistProfile(ctx, client, id)
+	if err != nil {
+		return artistData, err
+	}
+	if profile.Name == "" {
+		return artistData, &types.ParseError{What: "band page", Err: fmt.Errorf("Band page has no band name.")}
+	}
+
+	artistData.Name = profile.Name
+	artistData.URL = profile.URL
+	artistData.ID = id
+	artistData.Genre = profile.Genre
+	artistData.Country = profile.Country
+
+	return artistData, nil
+}
+
+// LookupArtistRecords retrieves the records of the band idOrURL points to
+// without searching it by name.
+func LookupArtistRecords(ctx context.Context, client scraper.Client, idOrURL string) ([]commontypes.Record, error) {
+	id, err := ParseArtistID(idOrURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetArtistRecordsWithContext(ctx, client, SearchArtistData{ID: id})
+}
@@ -0,0 +1,48 @@
+// +build integration_tests unit_tests
+
+package artists
+
+import (
+	"bytes"
+	"context"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestParseArtistID(t *testing.T) {
+	ids := map[string]string{
+		"88": "88",
+		"https://www.metal-archives.com/bands/Burzum/88":                      "88",
+		"https://www.metal-archives.com/bands/B%C3%B6lzer/3540351548/":        "3540351548",
+		"https://www.metal-archives.com/bands/Burzum/88#band_tab_discography": "88",
+	}
+
+	for idOrURL, expected := range ids {
+		id, err := ParseArtistID(idOrURL)
+		if err != nil || id != expected {
+			t.Errorf("'%s' band ID should be '%s', not '%s'.", idOrURL, expected, id)
+		}
+	}
+
+	for _, idOrURL := range []string{"Burzum", "https://www.metal-archives.com/albums/Burzum/Filosofem/93", ""} {
+		if _, err := ParseArtistID(idOrURL); err == nil {
+			t.Errorf("'%s' shouldn't be a band ID.", idOrURL)
+		}
+	}
+}
+
+func TestLookupArtist(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(burzumPage))}}})
+
+	artistData, err := LookupArtist(context.Background(), client, "https://www.metal-archives.com/bands/Burzum/88")
+
+	if err != nil {
+		t.Fatalf("LookupArtist shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if artistData.Name != "Burzum" || artistData.ID != "88" || artistData.URL != "https://www.metal-archives.com/bands/Burzum/88" || artistData.Genre != "Black Metal, Ambient" || artistData.Country != "Norway" {
+		t.Errorf("LookupArtist should return Burzum data, not '%v'.", artistData)
+	}
+}
@@ -1,94 +1,129 @@
 package artists
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
 	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
 	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
-	"io/ioutil"
-	"net/http"
+	"html"
+	"net/url"
 	"regexp"
 	"strings"
 )
 
-type SearchArtistData commontypes.Artist
+type SearchArtistData struct {
+	Name    string
+	URL     string
+	ID      string
+	Genre   string
+	Country string
+	Records []commontypes.Record
+	Aliases []string
+}
 
-func searchArtistAjax(client http.Client, artist string) ([][]string, error) {
+// SearchArtistResults holds every band returned by upstream search and the
+// amount of bands upstream reported.
+type SearchArtistResults struct {
+	Artists      []SearchArtistData
+	TotalRecords int
+}
 
-	var searchArtistData [][]string
-	artistString := strings.Replace(artist, " ", "+", -1)
-	url := fmt.Sprintf("https://www.metal-archives.com/search/ajax-band-search/?field=name&query=%s", artistString)
+func searchArtistAjax(ctx context.Context, client scraper.Client, artist string) (types.SearchAjaxData, error) {
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return searchArtistData, err
-	}
+	searchURL := fmt.Sprintf("/search/ajax-band-search/?field=name&query=%s", url.QueryEscape(artist))
 
-	req.Header.Set("User-Agent", "https://github.com/a-castellano/metal-archives-wrapper")
+	return client.FetchAjaxPages(ctx, scraper.SearchEndpoint, searchURL, 0, client.MaxResults)
+}
 
-	res, getErr := client.Do(req)
-	if getErr != nil {
-		return searchArtistData, getErr
-	}
+// artistFromLink returns the band a link points to.
+func artistFromLink(link types.BandLink) SearchArtistData {
+	return SearchArtistData{Name: link.Name, URL: link.URL, ID: link.ID}
+}
+
+func readSearchArtistRow(row []string) (SearchArtistData, error) {
+	aliasesre := regexp.MustCompile(`\(<strong>a\.k\.a\.</strong>\s*([^)]*)\)`)
 
-	body, readErr := ioutil.ReadAll(res.Body)
-	if readErr != nil {
-		return searchArtistData, readErr
+	var artistData SearchArtistData
+
+	if len(row) < 3 {
+		return artistData, &types.ParseError{What: "artist search", Err: fmt.Errorf("Artist search row has %d columns instead of 3.", len(row))}
 	}
 
-	searchArtist := types.SearchAjaxData{}
-	jsonErr := json.Unmarshal(body, &searchArtist)
-	if jsonErr != nil {
-		return searchArtistData, jsonErr
+	link, err := types.ReadBandLink(row[0])
+	if err != nil {
+		return artistData, &types.ParseError{What: "artist search", Err: err}
+	}
+	artistData = artistFromLink(link)
+	artistData.Genre = html.UnescapeString(row[1])
+	artistData.Country = html.UnescapeString(row[2])
+
+	aliasesMatch := aliasesre.FindAllStringSubmatch(row[0], -1)
+	if aliasesMatch != nil {
+		for _, alias := range strings.Split(html.UnescapeString(aliasesMatch[0][1]), ",") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				artistData.Aliases = append(artistData.Aliases, alias)
+			}
+		}
 	}
-	searchArtistData = searchArtist.Data
-	return searchArtistData, nil
+
+	return artistData, nil
 }
 
-func SearchArtist(client http.Client, artist string) (SearchArtistData, []SearchArtistData, error) {
+// GetSearchArtistResults returns every band found searching artist, walking
+// every upstream page up to client MaxResults bands.
+func GetSearchArtistResults(ctx context.Context, client scraper.Client, artist string) (SearchArtistResults, error) {
+	var results SearchArtistResults
 
-	var artistData SearchArtistData
-	var artistExtraData []SearchArtistData
+	data, err := searchArtistAjax(ctx, client, artist)
+	if err != nil {
+		return results, err
+	}
 
-	artistDatare := regexp.MustCompile(`^<a href=\"([^\"]+)\">([^<]+)</a>`)
-	artistIDre := regexp.MustCompile(`^[^\/]*\/\/[^\/]*\/[^\/]*\/[^\/]*\/([0-9]*)`)
+	return readSearchArtistResults(data)
+}
 
-	data, err := searchArtistAjax(client, artist)
+// readSearchArtistResults parses every band row of a search endpoint response.
+func readSearchArtistResults(data types.SearchAjaxData) (SearchArtistResults, error) {
+	var results SearchArtistResults
+	results.TotalRecords = data.TotalRecords
 
-	var found bool = false
+	for _, row := range data.Data {
+		artistData, rowErr := readSearchArtistRow(row)
+		if rowErr != nil {
+			return results, rowErr
+		}
+		results.Artists = append(results.Artists, artistData)
+	}
 
-	if err != nil {
-		return artistData, artistExtraData, err
-	} else {
-		for _, foundArtistData := range data {
-			match := artistDatare.FindAllStringSubmatch(foundArtistData[0], -1)
-			if strings.ToLower(match[0][2]) == strings.ToLower(artist) {
-				if !found {
-					artistData.URL = match[0][1]
-					artistData.Name = match[0][2]
-					artistData.Genre = foundArtistData[1]
-					artistData.Country = foundArtistData[2]
-					IDmatch := artistIDre.FindAllStringSubmatch(artistData.URL, -1)
-					artistData.ID = IDmatch[0][1]
-					found = true
-				} else {
-					extraData := SearchArtistData{}
-					extraData.URL = match[0][1]
-					extraData.Name = match[0][2]
-					extraData.Genre = foundArtistData[1]
-					extraData.Country = foundArtistData[2]
-					IDmatch := artistIDre.FindAllStringSubmatch(extraData.URL, -1)
-					extraData.ID = IDmatch[0][1]
-					artistExtraData = append(artistExtraData, extraData)
-				}
-			}
+	return results, nil
+}
+
+// artistMatchRank ranks artistData name against artist, bands only matching
+// through one of their aliases are ranked after the ones matching by name.
+func artistMatchRank(artist string, artistData SearchArtistData, mode types.MatchMode) int {
+	rank := types.MatchRank(artist, artistData.Name, mode)
+	if rank != types.NoMatchRank {
+		return rank
+	}
+	for _, alias := range artistData.Aliases {
+		if types.MatchRank(artist, alias, mode) != types.NoMatchRank {
+			return types.AliasMatchRank
 		}
 	}
+	return types.NoMatchRank
+}
 
-	if !found {
-		return artistData, artistExtraData, errors.New("No artist was found.")
+func SearchArtist(client scraper.Client, artist string) (SearchArtistData, []SearchArtistData, error) {
+	return SearchArtistWithContext(context.Background(), client, artist)
+}
+
+func SearchArtistWithContext(ctx context.Context, client scraper.Client, artist string) (SearchArtistData, []SearchArtistData, error) {
+
+	candidates, err := GetArtistCandidates(ctx, client, artist)
+	if err != nil {
+		return SearchArtistData{}, nil, err
 	}
 
-	return artistData, artistExtraData, nil
+	return SelectArtistMatches(candidates)
 }
@@ -4,13 +4,21 @@ package artists
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 )
 
 func TestSearchArtistAjaxNoArtists(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 0,
@@ -19,22 +27,22 @@ func TestSearchArtistAjaxNoArtists(t *testing.T) {
 	"aaData": [
 		]
 }
-	`))}}}
+	`))}}})
 
-	data, err := searchArtistAjax(client, "AnyArtist")
+	data, err := searchArtistAjax(context.Background(), client, "AnyArtist")
 
 	if err != nil {
 		t.Errorf("TestClientNoArtists shouldn't fail.")
 	}
 
-	if len(data) != 0 {
+	if len(data.Data) != 0 {
 		t.Errorf("TestClientNoArtists should return empty data.")
 	}
 
 }
 
 func TestSearchArtistAjaxBrokenJson(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 0,
@@ -42,9 +50,9 @@ func TestSearchArtistAjaxBrokenJson(t *testing.T) {
 	"sEcho": 0,
 	"aaData": [
 }
-	`))}}}
+	`))}}})
 
-	_, err := searchArtistAjax(client, "AnyArtist")
+	_, err := searchArtistAjax(context.Background(), client, "AnyArtist")
 
 	if err == nil {
 		t.Errorf("TestBrokenJson should fail because JSON response is broken.")
@@ -52,7 +60,7 @@ func TestSearchArtistAjaxBrokenJson(t *testing.T) {
 }
 
 func TestSearchArtistAjaxOneArtist(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 1,
@@ -65,21 +73,21 @@ func TestSearchArtistAjaxOneArtist(t *testing.T) {
 			"Norway"     		]
 				]
 }
-	`))}}}
+	`))}}})
 
-	data, err := searchArtistAjax(client, "AnyArtist")
+	data, err := searchArtistAjax(context.Background(), client, "AnyArtist")
 
 	if err != nil {
 		t.Errorf("TestClientNoArtists shouldn't fail.")
 	}
 
-	if len(data) != 1 {
+	if len(data.Data) != 1 {
 		t.Errorf("TestClientNoArtists should return one entry only.")
 	}
 }
 
 func TestSearchArtistAjaxMoreThanOneArtist(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 3,
@@ -102,21 +110,21 @@ func TestSearchArtistAjaxMoreThanOneArtist(t *testing.T) {
 			"Brazil"     		]
 				]
 }
-	`))}}}
+	`))}}})
 
-	data, err := searchArtistAjax(client, "AnyArtist")
+	data, err := searchArtistAjax(context.Background(), client, "AnyArtist")
 
 	if err != nil {
 		t.Errorf("TestSearchArtistAjaxMoreThanOneArtist shouldn't fail.")
 	}
 
-	if len(data) != 3 {
+	if len(data.Data) != 3 {
 		t.Errorf("TestSearchArtistAjaxMoreThanOneArtist should return three entries.")
 	}
 }
 
 func TestSearchArtistErrored(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 0,
@@ -124,7 +132,7 @@ func TestSearchArtistErrored(t *testing.T) {
 	"sEcho": 0,
 	"aaData": [
 }
-	`))}}}
+	`))}}})
 
 	data, extraData, err := SearchArtist(client, "AnyArtist")
 
@@ -158,7 +166,7 @@ func TestSearchArtistErrored(t *testing.T) {
 }
 
 func TestSearchArtistNotFound(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 0,
@@ -167,7 +175,7 @@ func TestSearchArtistNotFound(t *testing.T) {
 	"aaData": [
 		]
 }
-	`))}}}
+	`))}}})
 
 	data, extraData, err := SearchArtist(client, "AnyArtist")
 
@@ -205,7 +213,7 @@ func TestSearchArtistNotFound(t *testing.T) {
 }
 
 func TestSearchArtistNotMatch(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 3,
@@ -229,7 +237,7 @@ func TestSearchArtistNotMatch(t *testing.T) {
 				]
 }
 
-	`))}}}
+	`))}}})
 
 	data, extraData, err := SearchArtist(client, "AnyArtist")
 
@@ -268,7 +276,7 @@ func TestSearchArtistNotMatch(t *testing.T) {
 }
 
 func TestSearchArtistMatch(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 3,
@@ -292,7 +300,7 @@ func TestSearchArtistMatch(t *testing.T) {
 				]
 }
 
-	`))}}}
+	`))}}})
 
 	data, extraData, err := SearchArtist(client, "Burzum")
 
@@ -326,7 +334,7 @@ func TestSearchArtistMatch(t *testing.T) {
 }
 
 func TestSearchArtistMatchLowercase(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 3,
@@ -350,7 +358,7 @@ func TestSearchArtistMatchLowercase(t *testing.T) {
 				]
 }
 
-	`))}}}
+	`))}}})
 
 	data, extraData, err := SearchArtist(client, "burzum")
 
@@ -385,7 +393,7 @@ func TestSearchArtistMatchLowercase(t *testing.T) {
 }
 
 func TestSearchArtistMultipleMatches(t *testing.T) {
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 5,
@@ -418,7 +426,7 @@ func TestSearchArtistMultipleMatches(t *testing.T) {
 			"Poland"     		]
 				]
 }
-	`))}}}
+	`))}}})
 
 	data, extraData, err := SearchArtist(client, "Hypocrisy")
 
@@ -471,3 +479,205 @@ func TestSearchArtistMultipleMatches(t *testing.T) {
 	}
 
 }
+
+func TestSearchArtistNotFoundStatus(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewBufferString(`<html><body>Not Found</body></html>`))}}})
+
+	_, _, err := SearchArtist(client, "AnyArtist")
+
+	if !errors.Is(err, types.ErrNotFound) {
+		t.Errorf("SearchArtist should return ErrNotFound when upstream returns 404.")
+	}
+}
+
+func TestSearchArtistErrorsAreTyped(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`<html><body>Error page</body></html>`))}}})
+
+	_, _, err := SearchArtist(client, "AnyArtist")
+
+	if !errors.Is(err, types.ErrParse) {
+		t.Errorf("SearchArtist should return ErrParse when upstream response is not JSON.")
+	}
+
+	client.HTTPClient.Transport = &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`{"aaData": []}`))}}
+
+	_, _, err = SearchArtist(client, "AnyArtist")
+
+	if !errors.Is(err, types.ErrNoMatch) {
+		t.Errorf("SearchArtist should return ErrNoMatch when no artist is found.")
+	}
+}
+
+func TestGetSearchArtistResultsPaginated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("iDisplayStart"))
+		length, _ := strconv.Atoi(r.URL.Query().Get("iDisplayLength"))
+
+		page := types.SearchAjaxData{TotalRecords: 250, TotalDisplayRecords: 250, Data: [][]string{}}
+		for row := start; row < start+length && row < 250; row++ {
+			name := fmt.Sprintf("Abyss of %d", row)
+			if row == 230 {
+				name = "Abyss"
+			}
+			page.Data = append(page.Data, []string{fmt.Sprintf(`<a href="https://www.metal-archives.com/bands/Abyss/%d">%s</a>  <!-- 1.0 -->`, row, name), "Death Metal", "Germany"})
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	results, err := GetSearchArtistResults(context.Background(), client, "Abyss")
+
+	if err != nil {
+		t.Fatalf("GetSearchArtistResults shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(results.Artists) != 250 || results.TotalRecords != 250 {
+		t.Errorf("GetSearchArtistResults should return 250 artists, it returned %d of %d.", len(results.Artists), results.TotalRecords)
+	}
+
+	data, _, err := SearchArtist(client, "Abyss")
+
+	if err != nil {
+		t.Fatalf("SearchArtist shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if data.ID != "230" {
+		t.Errorf("Exact match is on second page and its ID is 230, not '%s'.", data.ID)
+	}
+}
+
+func TestGetSearchArtistResultsBrokenRow(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"iTotalRecords": 1,
+	"iTotalDisplayRecords": 1,
+	"aaData": [
+		["Not a link", "Black Metal", "Norway"]
+	]
+}
+	`))}}})
+
+	_, err := GetSearchArtistResults(context.Background(), client, "AnyArtist")
+
+	if !errors.Is(err, types.ErrParse) {
+		t.Errorf("Rows without artist link should return ErrParse, not '%v'.", err)
+	}
+}
+
+func TestSearchArtistNormalizedMatch(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"error": "",
+	"iTotalRecords": 3,
+	"iTotalDisplayRecords": 3,
+	"sEcho": 0,
+	"aaData": [
+				[
+			"<a href=\"https://www.metal-archives.com/bands/Bolzer/1\">Bolzer</a>  <!-- 11.432714 -->" ,
+			"Thrash Metal" ,
+			"Germany"     		]
+				,
+						[
+			"<a href=\"https://www.metal-archives.com/bands/B%C3%B6lzer/3540351548\">Bölzer</a>  <!-- 11.432714 -->" ,
+			"Black/Death Metal" ,
+			"Switzerland"     		]
+				,
+						[
+			"<a href=\"https://www.metal-archives.com/bands/Bolzer_Bolzer/2\">Bolzer Bolzer</a>  <!-- 5.716357 -->" ,
+			"Black Metal" ,
+			"Brazil"     		]
+				]
+}
+	`))}}})
+
+	data, extraData, err := SearchArtist(client, "Bölzer")
+
+	if err != nil {
+		t.Fatalf("SearchArtist shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if data.ID != "3540351548" {
+		t.Errorf("Exact byte match 'Bölzer' should be ranked first, found artist ID is '%s'.", data.ID)
+	}
+
+	if len(extraData) != 1 || extraData[0].ID != "1" {
+		t.Errorf("'Bolzer' should be returned as extra data.")
+	}
+}
+
+func TestSearchArtistAliasMatch(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"error": "",
+	"iTotalRecords": 2,
+	"iTotalDisplayRecords": 2,
+	"sEcho": 0,
+	"aaData": [
+				[
+			"<a href=\"https://www.metal-archives.com/bands/The_Polo_Hypocrisy/47897\">The Polo Hypocrisy</a> (<strong>a.k.a.</strong> T.P.H., TPH) <!-- 5.3701577 -->" ,
+			"Death Metal" ,
+			"Sweden"     		]
+				,
+						[
+			"<a href=\"https://www.metal-archives.com/bands/TPH/1\">TPH</a>  <!-- 1.2505064 -->" ,
+			"Black Metal" ,
+			"Brazil"     		]
+				]
+}
+	`))}}})
+
+	data, extraData, err := SearchArtist(client, "TPH")
+
+	if err != nil {
+		t.Fatalf("SearchArtist shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if data.ID != "1" {
+		t.Errorf("Name match should be ranked before alias match, found artist ID is '%s'.", data.ID)
+	}
+
+	if len(extraData) != 1 || extraData[0].ID != "47897" {
+		t.Fatalf("'The Polo Hypocrisy' should be returned as extra data through its alias.")
+	}
+
+	if len(extraData[0].Aliases) != 2 || extraData[0].Aliases[0] != "T.P.H." || extraData[0].Aliases[1] != "TPH" {
+		t.Errorf("'The Polo Hypocrisy' aliases should be 'T.P.H.' and 'TPH', not '%v'.", extraData[0].Aliases)
+	}
+
+	if len(data.Aliases) != 0 {
+		t.Errorf("'TPH' shouldn't have aliases, not '%v'.", data.Aliases)
+	}
+}
+
+func TestSearchArtistAmpersand(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		w.Write([]byte(`{"iTotalRecords": 1, "iTotalDisplayRecords": 1, "aaData": [["<a href=\"https://www.metal-archives.com/bands/Simon_%26_Garfunkel/1234\">Simon &amp; Garfunkel</a>  <!-- 1.0 -->", "Folk", "United States"]]}`))
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	data, _, err := SearchArtist(client, "Simon & Garfunkel")
+
+	if err != nil {
+		t.Fatalf("SearchArtist shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if query != "Simon & Garfunkel" {
+		t.Errorf("Searched name should reach upstream whole, not as '%s'.", query)
+	}
+
+	if data.Name != "Simon & Garfunkel" || data.ID != "1234" {
+		t.Errorf("Found band name should be unescaped, not '%s'.", data.Name)
+	}
+
+	if _, _, err := SearchArtist(client, "Simon and Garfunkel"); err != nil {
+		t.Errorf("'Simon and Garfunkel' should match 'Simon & Garfunkel', error was '%s'.", err.Error())
+	}
+}
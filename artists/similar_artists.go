@@ -0,0 +1,86 @@
+package artists
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"golang.org/x/net/html"
+)
+
+// SimilarArtist is a band recommended as similar, Score is the amount of
+// user votes.
+type SimilarArtist struct {
+	Artist SearchArtistData
+	Score  int
+}
+
+func readSimilarArtist(cells []*html.Node) (SimilarArtist, error) {
+	var similar SimilarArtist
+
+	link := types.FindElement(cells[0], "a", nil)
+	if link == nil {
+		return similar, fmt.Errorf("Similar artist row has no artist link.")
+	}
+	similar.Artist.Name = types.Text(link)
+	similar.Artist.URL = types.Attribute(link, "href")
+	id, found := types.BandID(similar.Artist.URL)
+	if !found {
+		return similar, fmt.Errorf("Similar artist URL '%s' has no ID.", similar.Artist.URL)
+	}
+	similar.Artist.ID = id
+	similar.Artist.Country = types.Text(cells[1])
+	similar.Artist.Genre = types.Text(cells[2])
+
+	score, err := strconv.Atoi(types.Text(cells[3]))
+	if err != nil {
+		return similar, fmt.Errorf("Similar artist score '%s' is not a number.", types.Text(cells[3]))
+	}
+	similar.Score = score
+
+	return similar, nil
+}
+
+// GetSimilarArtists retrieves bands recommended as similar to artistData,
+// sorted by score.
+func GetSimilarArtists(ctx context.Context, client scraper.Client, artistData SearchArtistData) ([]SimilarArtist, error) {
+	var similarArtists []SimilarArtist
+
+	url := fmt.Sprintf("/band/ajax-recommendations/id/%s?showMoreSimilar=1", artistData.ID)
+	body, err := client.GetWithContext(ctx, scraper.BandEndpoint, url)
+	if err != nil {
+		return similarArtists, err
+	}
+
+	doc, err := html.Parse(strings.NewReader(string(body)))
+	if err != nil {
+		return similarArtists, &types.ParseError{What: "similar artists", Err: err}
+	}
+
+	// Header, "show more" and "no recommendations" rows have no artist cells.
+	for _, row := range types.FindElements(doc, "tr", nil) {
+		cells := types.ChildElements(row, "td")
+		if len(cells) < 4 {
+			continue
+		}
+		similar, rowErr := readSimilarArtist(cells)
+		if rowErr != nil {
+			return similarArtists, &types.ParseError{What: "similar artists", Err: rowErr}
+		}
+		similarArtists = append(similarArtists, similar)
+	}
+
+	if len(similarArtists) == 0 {
+		return similarArtists, &types.NoMatchError{Message: "No similar artists were found."}
+	}
+
+	sort.SliceStable(similarArtists, func(i, j int) bool {
+		return similarArtists[i].Score > similarArtists[j].Score
+	})
+
+	return similarArtists, nil
+}
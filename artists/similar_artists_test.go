@@ -0,0 +1,89 @@
+// +build integration_tests unit_tests
+
+package artists
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestGetSimilarArtists(t *testing.T) {
+	artistData := SearchArtistData{Name: "Burzum", ID: "88"}
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+<table id="artist_list" class="display" cellpadding="0" cellspacing="0">
+	<thead>
+		<tr>
+			<th>Name</th>
+			<th>Country</th>
+			<th>Genre</th>
+			<th>Score</th>
+		</tr>
+	</thead>
+	<tbody>
+		<tr id="recRow_1">
+			<td><a href="https://www.metal-archives.com/bands/Darkthrone/146">Darkthrone</a></td>
+			<td>Norway</td>
+			<td>Black Metal</td>
+			<td><span id="score_1" class="bold">163</span></td>
+		</tr>
+		<tr id="recRow_2">
+			<td><a href="https://www.metal-archives.com/bands/Mayhem/67">Mayhem</a></td>
+			<td>Norway</td>
+			<td>Black Metal</td>
+			<td><span id="score_2" class="bold">98</span></td>
+		</tr>
+		<tr id="recRow_3">
+			<td><a href="https://www.metal-archives.com/bands/Paysage_d%27Hiver/7281">Paysage d'Hiver</a></td>
+			<td>Switzerland</td>
+			<td>Black Metal, Ambient</td>
+			<td><span id="score_3" class="bold">242</span></td>
+		</tr>
+		<tr id="show_more">
+			<td colspan="4"><a href="#" onclick="return false;">show more</a></td>
+		</tr>
+	</tbody>
+</table>
+`))}}})
+
+	similarArtists, err := GetSimilarArtists(context.Background(), client, artistData)
+
+	if err != nil {
+		t.Fatalf("GetSimilarArtists shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(similarArtists) != 3 {
+		t.Fatalf("Burzum should have 3 similar artists, not %d.", len(similarArtists))
+	}
+
+	first := similarArtists[0]
+	if first.Artist.Name != "Paysage d'Hiver" || first.Artist.ID != "7281" || first.Artist.Country != "Switzerland" || first.Artist.Genre != "Black Metal, Ambient" || first.Score != 242 {
+		t.Errorf("Most voted similar artist should be Paysage d'Hiver with 242 votes, not '%v'.", first)
+	}
+
+	if similarArtists[1].Artist.Name != "Darkthrone" || similarArtists[2].Score != 98 {
+		t.Errorf("Similar artists should be sorted by score.")
+	}
+}
+
+func TestGetSimilarArtistsNone(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+<table id="artist_list" class="display">
+	<tbody>
+		<tr><td colspan="4">No similar artist has been recommended yet.</td></tr>
+	</tbody>
+</table>
+`))}}})
+
+	_, err := GetSimilarArtists(context.Background(), client, SearchArtistData{ID: "1"})
+
+	if !errors.Is(err, types.ErrNoMatch) {
+		t.Errorf("Bands without recommendations should return ErrNoMatch, not '%v'.", err)
+	}
+}
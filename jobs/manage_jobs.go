@@ -1,15 +1,107 @@
 package jobs
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
 
 	commontypes "github.com/a-castellano/music-manager-common-types/types"
 	"github.com/a-castellano/music-manager-metal-archives-wrapper/artists"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
 )
 
-func ProcessJob(data []byte, origin string, client http.Client) (bool, []byte, error) {
+// retrievalError builds the error reported to Job Manager, upstream failures
+// are told apart from missing data so they can be retried later.
+func retrievalError(what string, err error) error {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%s retrieval failed: job was cancelled: %w", what, err)
+	case errors.Is(err, types.ErrBlocked):
+		return fmt.Errorf("%s retrieval failed: Metal Archives is blocking requests, data may exist upstream: %w", what, err)
+	case errors.Is(err, types.ErrRateLimited):
+		return fmt.Errorf("%s retrieval failed: Metal Archives is rate limiting requests, retry later: %w", what, err)
+	case errors.Is(err, types.ErrUpstreamUnavailable):
+		return fmt.Errorf("%s retrieval failed: Metal Archives is unavailable, retry later: %w", what, err)
+	case errors.Is(err, types.ErrNotFound):
+		return fmt.Errorf("%s retrieval failed: Metal Archives page was not found: %w", what, err)
+	default:
+		return fmt.Errorf("%s retrieval failed: %w", what, err)
+	}
+}
+
+// MaxSuggestions caps how many "did you mean" bands are returned to Job
+// Manager when no band matches the searched name.
+const MaxSuggestions = 10
+
+func artistPayload(data artists.SearchArtistData) types.Artist {
+	artistData := types.Artist{}
+	artistData.Name = data.Name
+	artistData.URL = data.URL
+	artistData.ID = data.ID
+	artistData.Country = data.Country
+	artistData.Genre = data.Genre
+	artistData.Aliases = data.Aliases
+	return artistData
+}
+
+// searchArtist searches artist as artists.SearchArtist does, choosing among
+// bands sharing its name with hints when there are any. When no band matches
+// the closest ones are returned as ExtraData along with the error.
+func searchArtist(ctx context.Context, client scraper.Client, artist string, hints types.ArtistHints) (types.ArtistInfo, error) {
+	var artistinfo types.ArtistInfo
+
+	candidates, err := artists.GetArtistCandidates(ctx, client, artist)
+	if err != nil {
+		return artistinfo, err
+	}
+
+	disambiguation, err := artists.DisambiguateCandidates(ctx, client, candidates, hints)
+	if errors.Is(err, types.ErrNoMatch) {
+		// Candidates are sorted by similarity when none matches.
+		for i := 0; i < len(candidates) && i < MaxSuggestions; i++ {
+			artistinfo.ExtraData = append(artistinfo.ExtraData, artistPayload(candidates[i].Artist))
+		}
+		return artistinfo, err
+	}
+	if err != nil {
+		return artistinfo, err
+	}
+
+	artistinfo.Data = artistPayload(disambiguation.Artist)
+	for _, extraArtist := range disambiguation.ExtraData {
+		artistinfo.ExtraData = append(artistinfo.ExtraData, artistPayload(extraArtist))
+	}
+	if !hints.Empty() {
+		artistinfo.Confidence = disambiguation.Confidence
+		artistinfo.MatchedHints = disambiguation.MatchedHints
+	}
+
+	return artistinfo, nil
+}
+
+// lookupArtist retrieves the band of an ArtistData retrieval. Its Data holds
+// a commontypes.Artist whose ID or URL points to the band, the retrieval
+// Artist field may hold the ID or URL instead.
+func lookupArtist(ctx context.Context, client scraper.Client, retrievalData commontypes.InfoRetrieval) (artists.SearchArtistData, error) {
+	idOrURL := retrievalData.Artist
+	if len(retrievalData.Data) > 0 {
+		artist, err := commontypes.DecodeArtist(retrievalData.Data)
+		if err != nil {
+			return artists.SearchArtistData{}, fmt.Errorf("Artist data could not be decoded: %w", err)
+		}
+		idOrURL = artist.ID
+		if idOrURL == "" {
+			idOrURL = artist.URL
+		}
+	}
+
+	return artists.LookupArtist(ctx, client, idOrURL)
+}
+
+// ProcessJob runs the received job, ctx is passed down to every scraper so
+// cancelling it stops in-flight requests.
+func ProcessJob(ctx context.Context, data []byte, origin string, client scraper.Client) (bool, []byte, error) {
 
 	receivedJob, decodeJobErr := commontypes.DecodeJob(data)
 	var job commontypes.Job
@@ -31,37 +123,41 @@ func ProcessJob(data []byte, origin string, client http.Client) (bool, []byte, e
 			if err == nil {
 				switch retrievalData.Type {
 				case commontypes.ArtistName:
-					data, extraData, errSearchArtist := artists.SearchArtist(client, retrievalData.Artist)
+					var hints types.ArtistHints
+					if len(retrievalData.Data) > 0 {
+						hints, err = types.DecodeArtistHints(retrievalData.Data)
+						if err != nil {
+							err = fmt.Errorf("Artist retrieval failed: hints could not be decoded: %w", err)
+							job.Error = err.Error()
+							job.Status = false
+							break
+						}
+					}
+					artistinfo, errSearchArtist := searchArtist(ctx, client, retrievalData.Artist, hints)
+					if len(artistinfo.ExtraData) > 0 || errSearchArtist == nil {
+						job.Result, _ = types.EncodeArtistInfo(artistinfo)
+					}
 					// If there is no artist info job must return empty data, but it is not an error.
 					if errSearchArtist != nil {
-						err = errors.New(errors.New("Artist retrieval failed: ").Error() + errSearchArtist.Error())
+						err = retrievalError("Artist", errSearchArtist)
 						job.Error = err.Error()
 						job.Status = false
 					} else {
-						artistData := commontypes.Artist{}
-						artistData.Name = data.Name
-						artistData.URL = data.URL
-						artistData.ID = data.ID
-						artistData.Country = data.Country
-						artistData.Genre = data.Genre
-						artistinfo := commontypes.ArtistInfo{}
-
-						artistinfo.Data = artistData
-
-						for _, extraArtist := range extraData {
-							var artist commontypes.Artist
-							artist.Name = extraArtist.Name
-							artist.URL = extraArtist.URL
-							artist.ID = extraArtist.ID
-							artist.Country = extraArtist.Country
-							artist.Genre = extraArtist.Genre
-							artistinfo.ExtraData = append(artistinfo.ExtraData, artist)
-						}
-						job.Result, _ = commontypes.EncodeArtistInfo(artistinfo)
+						job.Status = true
+					}
+				case commontypes.ArtistData:
+					artistData, errLookupArtist := lookupArtist(ctx, client, retrievalData)
+					if errLookupArtist != nil {
+						err = retrievalError("Artist", errLookupArtist)
+						job.Error = err.Error()
+						job.Status = false
+					} else {
+						artistinfo := types.ArtistInfo{Data: artistPayload(artistData)}
+						job.Result, _ = types.EncodeArtistInfo(artistinfo)
 						job.Status = true
 					}
 				default:
-					err = errors.New("Music Manager Metal Archives Wrapper - ArtistInfoRetrieval type should be only ArtistName.")
+					err = errors.New("Music Manager Metal Archives Wrapper - ArtistInfoRetrieval type should be only ArtistName or ArtistData.")
 					job.Status = false
 					job.Error = err.Error()
 				}
@@ -4,12 +4,19 @@ package jobs
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	commontypes "github.com/a-castellano/music-manager-common-types/types"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
 )
 
 type RoundTripperMock struct {
@@ -18,6 +25,10 @@ type RoundTripperMock struct {
 }
 
 func (rtm *RoundTripperMock) RoundTrip(*http.Request) (*http.Response, error) {
+	// Mocked responses without status are successful ones.
+	if rtm.Response != nil && rtm.Response.StatusCode == 0 {
+		rtm.Response.StatusCode = http.StatusOK
+	}
 	return rtm.Response, rtm.RespErr
 }
 
@@ -25,7 +36,7 @@ func TestProcessJobEmptyData(t *testing.T) {
 
 	var emptyData []byte
 
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 3,
@@ -48,11 +59,11 @@ func TestProcessJobEmptyData(t *testing.T) {
 			"Brazil"     		]
 				]
 }
-	`))}}}
+	`))}}})
 
 	origin := "MetalArchivesWrapper"
 
-	die, jobResult, err := ProcessJob(emptyData, origin, client)
+	die, jobResult, err := ProcessJob(context.Background(), emptyData, origin, client)
 
 	if err.Error() != "Empty job data received." {
 		t.Errorf("Message with failed data should return 'Empty data received.' error, not '%s'.", err.Error())
@@ -86,7 +97,7 @@ func TestProcessJobErrorOnArtist(t *testing.T) {
 
 	encodedJob, _ := commontypes.EncodeJob(job)
 
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 3,
@@ -103,10 +114,10 @@ func TestProcessJobErrorOnArtist(t *testing.T) {
 			"Black Metal" ,
 				]
 }
-	`))}}}
+	`))}}})
 
 	origin := "MetalArchivesWrapper"
-	die, jobResult, err := ProcessJob(encodedJob, origin, client)
+	die, jobResult, err := ProcessJob(context.Background(), encodedJob, origin, client)
 
 	if err != nil {
 		if !strings.HasPrefix(err.Error(), "Artist retrieval failed: invalid character") {
@@ -159,7 +170,7 @@ func TestProcessJobOneArtist(t *testing.T) {
 
 	encodedJob, _ := commontypes.EncodeJob(job)
 
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 3,
@@ -182,10 +193,10 @@ func TestProcessJobOneArtist(t *testing.T) {
 			"Brazil"     		]
 				]
 }
-	`))}}}
+	`))}}})
 
 	origin := "MetalArchivesWrapper"
-	die, jobResult, err := ProcessJob(encodedJob, origin, client)
+	die, jobResult, err := ProcessJob(context.Background(), encodedJob, origin, client)
 
 	if err != nil {
 		if err.Error() != "Empty data received." {
@@ -224,6 +235,290 @@ func TestProcessJobOneArtist(t *testing.T) {
 
 }
 
+func TestProcessJobArtistAliases(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistName
+	infoRetrieval.Artist = "Krimparturr"
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.ID = "jobIdHash"
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"error": "",
+	"iTotalRecords": 1,
+	"iTotalDisplayRecords": 1,
+	"sEcho": 0,
+	"aaData": [
+				[
+			"<a href=\"https://www.metal-archives.com/bands/Krimparturr/21151\">Krimparturr</a> (<strong>a.k.a.</strong> Krimpartûrr Bürzum Shi-Hai) <!-- 1.2505064 -->" ,
+			"Black Metal" ,
+			"Brazil"     		]
+				]
+}
+	`))}}})
+
+	_, jobResult, err := ProcessJob(context.Background(), encodedJob, "MetalArchivesWrapper", client)
+
+	if err != nil {
+		t.Fatalf("ProcessJob shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	processedJob, _ := commontypes.DecodeJob(jobResult)
+	artistInfo, artistInfoDecodeError := types.DecodeArtistInfo(processedJob.Result)
+	if artistInfoDecodeError != nil {
+		t.Fatalf("Artist info decoding shouldn't fail, error was '%s'.", artistInfoDecodeError.Error())
+	}
+
+	if len(artistInfo.Data.Aliases) != 1 || artistInfo.Data.Aliases[0] != "Krimpartûrr Bürzum Shi-Hai" {
+		t.Errorf("Artist info aliases should be 'Krimpartûrr Bürzum Shi-Hai', not '%v'.", artistInfo.Data.Aliases)
+	}
+}
+
+func TestProcessJobArtistSuggestions(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistName
+	infoRetrieval.Artist = "Burzun"
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.ID = "jobIdHash"
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"error": "",
+	"iTotalRecords": 2,
+	"iTotalDisplayRecords": 2,
+	"sEcho": 0,
+	"aaData": [
+				[
+			"<a href=\"https://www.metal-archives.com/bands/Down_to_Burzum/3540435931\">Down to Burzum</a>  <!-- 5.716357 -->" ,
+			"Black Metal" ,
+			"Brazil"     		]
+				,
+						[
+			"<a href=\"https://www.metal-archives.com/bands/Burzum/88\">Burzum</a>  <!-- 11.432714 -->" ,
+			"Black Metal, Ambient" ,
+			"Norway"     		]
+				]
+}
+	`))}}})
+
+	_, jobResult, err := ProcessJob(context.Background(), encodedJob, "MetalArchivesWrapper", client)
+
+	if !errors.Is(err, types.ErrNoMatch) {
+		t.Errorf("ProcessJob error should be ErrNoMatch, not '%v'.", err)
+	}
+
+	processedJob, _ := commontypes.DecodeJob(jobResult)
+	if processedJob.Error != "Artist retrieval failed: No artist was found." {
+		t.Errorf("processedJob.Error should be 'Artist retrieval failed: No artist was found.', not '%s'.", processedJob.Error)
+	}
+
+	artistInfo, artistInfoDecodeError := commontypes.DecodeArtistInfo(processedJob.Result)
+	if artistInfoDecodeError != nil {
+		t.Fatalf("Suggestions decoding shouldn't fail, error was '%s'.", artistInfoDecodeError.Error())
+	}
+
+	if artistInfo.Data.Name != "" {
+		t.Errorf("Suggestions shouldn't have artist data, found '%s'.", artistInfo.Data.Name)
+	}
+
+	if len(artistInfo.ExtraData) != 2 || artistInfo.ExtraData[0].Name != "Burzum" {
+		t.Errorf("'Burzum' should be the first suggestion for 'Burzun'.")
+	}
+}
+
+func TestProcessJobArtistHints(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistName
+	infoRetrieval.Artist = "Hypocrisy"
+	infoRetrieval.Data, _ = types.EncodeArtistHints(types.ArtistHints{Country: "United States"})
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.ID = "jobIdHash"
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"error": "",
+	"iTotalRecords": 2,
+	"iTotalDisplayRecords": 2,
+	"sEcho": 0,
+	"aaData": [
+				[
+			"<a href=\"https://www.metal-archives.com/bands/Hypocrisy/96\">Hypocrisy</a>  <!-- 10.740315 -->" ,
+			"Death Metal (early), Melodic Death Metal (later)" ,
+			"Sweden"     		]
+				,
+						[
+			"<a href=\"https://www.metal-archives.com/bands/Hypocrisy/56165\">Hypocrisy</a>  <!-- 10.740315 -->" ,
+			"Power/Thrash Metal" ,
+			"United States"     		]
+				]
+}
+	`))}}})
+
+	_, jobResult, err := ProcessJob(context.Background(), encodedJob, "MetalArchivesWrapper", client)
+
+	if err != nil {
+		t.Fatalf("ProcessJob shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	processedJob, _ := commontypes.DecodeJob(jobResult)
+	artistInfo, artistInfoDecodeError := types.DecodeArtistInfo(processedJob.Result)
+	if artistInfoDecodeError != nil {
+		t.Fatalf("Artist info decoding shouldn't fail, error was '%s'.", artistInfoDecodeError.Error())
+	}
+
+	if artistInfo.Data.ID != "56165" {
+		t.Errorf("Hypocrisy from United States should be chosen, not '%s'.", artistInfo.Data.ID)
+	}
+
+	if artistInfo.Confidence != 1 || len(artistInfo.MatchedHints) != 1 || artistInfo.MatchedHints[0] != "country" {
+		t.Errorf("Country hint should have chosen the artist with confidence 1, not %f with '%v'.", artistInfo.Confidence, artistInfo.MatchedHints)
+	}
+}
+
+func TestProcessJobArtistBrokenHints(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistName
+	infoRetrieval.Artist = "Hypocrisy"
+	infoRetrieval.Data = []byte("not hints")
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{RespErr: errors.New("no request should be sent")}})
+
+	_, jobResult, err := ProcessJob(context.Background(), encodedJob, "MetalArchivesWrapper", client)
+
+	if err == nil || !strings.HasPrefix(err.Error(), "Artist retrieval failed: hints could not be decoded: ") {
+		t.Errorf("ProcessJob should fail decoding hints, not '%v'.", err)
+	}
+
+	processedJob, _ := commontypes.DecodeJob(jobResult)
+	if processedJob.Status != false {
+		t.Errorf("job status should be false, hints could not be decoded.")
+	}
+}
+
+func TestProcessJobArtistByID(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistData
+	infoRetrieval.Data, _ = commontypes.EncodeArtist(commontypes.Artist{ID: "88"})
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.ID = "jobIdHash"
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		fmt.Fprint(w, `
+<h1 class="band_name"><a href="https://www.metal-archives.com/bands/Burzum/88">Burzum</a></h1>
+<div id="band_stats">
+	<dl>
+		<dt>Country of origin:</dt><dd><a href="https://www.metal-archives.com/lists/NO">Norway</a></dd>
+		<dt>Genre:</dt><dd>Black Metal, Ambient</dd>
+	</dl>
+</div>`)
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	_, jobResult, err := ProcessJob(context.Background(), encodedJob, "MetalArchivesWrapper", client)
+
+	if err != nil {
+		t.Fatalf("ProcessJob shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if requestedPath != "/bands/_/88" {
+		t.Errorf("Band page should be requested instead of searching, requested path was '%s'.", requestedPath)
+	}
+
+	processedJob, _ := commontypes.DecodeJob(jobResult)
+	artistInfo, artistInfoDecodeError := commontypes.DecodeArtistInfo(processedJob.Result)
+	if artistInfoDecodeError != nil {
+		t.Fatalf("Artist info decoding shouldn't fail, error was '%s'.", artistInfoDecodeError.Error())
+	}
+
+	if artistInfo.Data.Name != "Burzum" || artistInfo.Data.ID != "88" || artistInfo.Data.Country != "Norway" || artistInfo.Data.Genre != "Black Metal, Ambient" {
+		t.Errorf("Artist info should hold Burzum data, not '%v'.", artistInfo.Data)
+	}
+
+	if processedJob.Status != true {
+		t.Errorf("job status should be true, there was no errors processing the Job.")
+	}
+}
+
+func TestProcessJobArtistByURLNotFound(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistData
+	infoRetrieval.Artist = "https://www.metal-archives.com/bands/Nonexistent/1"
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewBufferString(`Not Found`))}}})
+
+	_, jobResult, err := ProcessJob(context.Background(), encodedJob, "MetalArchivesWrapper", client)
+
+	if !errors.Is(err, types.ErrNotFound) {
+		t.Errorf("ProcessJob error should be ErrNotFound, not '%v'.", err)
+	}
+
+	processedJob, _ := commontypes.DecodeJob(jobResult)
+	if processedJob.Status != false {
+		t.Errorf("job status should be false, band was not found.")
+	}
+}
+
 func TestProcessJobMoreThanOneArtist(t *testing.T) {
 
 	var infoRetrieval commontypes.InfoRetrieval
@@ -242,7 +537,7 @@ func TestProcessJobMoreThanOneArtist(t *testing.T) {
 
 	encodedJob, _ := commontypes.EncodeJob(job)
 
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 5,
@@ -275,10 +570,10 @@ func TestProcessJobMoreThanOneArtist(t *testing.T) {
 			"Poland"     		]
 				]
 }
-	`))}}}
+	`))}}})
 
 	origin := "MetalArchivesWrapper"
-	die, jobResult, err := ProcessJob(encodedJob, origin, client)
+	die, jobResult, err := ProcessJob(context.Background(), encodedJob, origin, client)
 
 	if err != nil {
 		if err.Error() != "Empty data received." {
@@ -314,7 +609,7 @@ func TestProcessJobNoArtists(t *testing.T) {
 
 	encodedJob, _ := commontypes.EncodeJob(job)
 
-	client := http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
 {
 	"error": "",
 	"iTotalRecords": 0,
@@ -323,10 +618,10 @@ func TestProcessJobNoArtists(t *testing.T) {
 	"aaData": [
 		]
 }
-	`))}}}
+	`))}}})
 
 	origin := "MetalArchivesWrapper"
-	die, jobResult, err := ProcessJob(encodedJob, origin, client)
+	die, jobResult, err := ProcessJob(context.Background(), encodedJob, origin, client)
 
 	if err != nil {
 		if err.Error() != "Artist retrieval failed: No artist was found." {
@@ -353,3 +648,190 @@ func TestProcessJobNoArtists(t *testing.T) {
 		t.Errorf("job status should be false, no artist was found.")
 	}
 }
+
+func TestProcessJobArtistRateLimited(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistName
+	infoRetrieval.Artist = "Burzum"
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.ID = "jobIdHash"
+	job.Status = true
+	job.Finished = false
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{StatusCode: http.StatusTooManyRequests, Body: ioutil.NopCloser(bytes.NewBufferString(`Too Many Requests`))}}})
+	client.Retry.MaxAttempts = 1
+
+	origin := "MetalArchivesWrapper"
+	die, jobResult, err := ProcessJob(context.Background(), encodedJob, origin, client)
+
+	if err == nil {
+		t.Fatalf("ProcessJob should fail when upstream is rate limiting requests.")
+	}
+
+	if !errors.Is(err, types.ErrRateLimited) {
+		t.Errorf("ProcessJob error should be ErrRateLimited, not '%s'.", err.Error())
+	}
+
+	if die == true {
+		t.Errorf("Rate limited jobs do not stop this service.")
+	}
+
+	decodedJob, _ := commontypes.DecodeJob(jobResult)
+	if !strings.HasPrefix(decodedJob.Error, "Artist retrieval failed: Metal Archives is rate limiting requests, retry later: ") {
+		t.Errorf("decodedJob.Error should tell upstream is rate limiting requests, not '%s'.", decodedJob.Error)
+	}
+
+	if decodedJob.Status != false {
+		t.Errorf("job status should be false, upstream is rate limiting requests.")
+	}
+}
+
+func TestProcessJobNoArtistsIsNoMatch(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistName
+	infoRetrieval.Artist = "AnyArtist"
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.ID = "jobIdHash"
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+{
+	"error": "",
+	"iTotalRecords": 0,
+	"iTotalDisplayRecords": 0,
+	"sEcho": 0,
+	"aaData": [
+		]
+}
+	`))}}})
+
+	_, _, err := ProcessJob(context.Background(), encodedJob, "MetalArchivesWrapper", client)
+
+	if !errors.Is(err, types.ErrNoMatch) {
+		t.Errorf("ProcessJob error should be ErrNoMatch when no artist is found.")
+	}
+}
+
+func TestProcessJobCancelled(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistName
+	infoRetrieval.Artist = "Burzum"
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.ID = "jobIdHash"
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{RespErr: context.Canceled}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, jobResult, err := ProcessJob(ctx, encodedJob, "MetalArchivesWrapper", client)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ProcessJob error should be context.Canceled when job context is cancelled, not '%v'.", err)
+	}
+
+	decodedJob, _ := commontypes.DecodeJob(jobResult)
+	if decodedJob.Error != "Artist retrieval failed: job was cancelled: context canceled" {
+		t.Errorf("decodedJob.Error should be 'Artist retrieval failed: job was cancelled: context canceled', not '%s'.", decodedJob.Error)
+	}
+}
+
+func TestProcessJobTimedOut(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistName
+	infoRetrieval.Artist = "Burzum"
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.ID = "jobIdHash"
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	// Upstream never answers, only the job deadline stops the request.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := scraper.NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, jobResult, err := ProcessJob(ctx, encodedJob, "MetalArchivesWrapper", client)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ProcessJob error should be context.DeadlineExceeded when job times out, not '%v'.", err)
+	}
+
+	decodedJob, _ := commontypes.DecodeJob(jobResult)
+	if decodedJob.Error != "Artist retrieval failed: job was cancelled: context deadline exceeded" {
+		t.Errorf("decodedJob.Error should be 'Artist retrieval failed: job was cancelled: context deadline exceeded', not '%s'.", decodedJob.Error)
+	}
+}
+
+func TestProcessJobArtistBlocked(t *testing.T) {
+
+	var infoRetrieval commontypes.InfoRetrieval
+	var job commontypes.Job
+
+	infoRetrieval.Type = commontypes.ArtistName
+	infoRetrieval.Artist = "Burzum"
+
+	retrievalData, _ := commontypes.EncodeInfoRetrieval(infoRetrieval)
+
+	job.Data = retrievalData
+	job.ID = "jobIdHash"
+	job.Type = commontypes.ArtistInfoRetrieval
+
+	encodedJob, _ := commontypes.EncodeJob(job)
+
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Server": []string{"cloudflare"}}, Body: ioutil.NopCloser(bytes.NewBufferString(`<html><head><title>Just a moment...</title></head></html>`))}}})
+
+	_, jobResult, err := ProcessJob(context.Background(), encodedJob, "MetalArchivesWrapper", client)
+
+	if !errors.Is(err, types.ErrBlocked) {
+		t.Errorf("ProcessJob error should be ErrBlocked when upstream serves a challenge page, not '%v'.", err)
+	}
+
+	decodedJob, _ := commontypes.DecodeJob(jobResult)
+	if !strings.HasPrefix(decodedJob.Error, "Artist retrieval failed: Metal Archives is blocking requests, data may exist upstream: ") {
+		t.Errorf("decodedJob.Error should tell upstream is blocking requests, not '%s'.", decodedJob.Error)
+	}
+
+	if decodedJob.Status != false {
+		t.Errorf("job status should be false, upstream is blocking requests.")
+	}
+}
@@ -1,18 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	config "github.com/a-castellano/music-manager-config-reader/config_reader"
 	queues "github.com/a-castellano/music-manager-metal-archives-wrapper/queues"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
 func main() {
 
-	client := http.Client{
+	httpClient := http.Client{
 		Timeout: time.Second * 5, // Maximum of 5 secs
 	}
 
@@ -20,13 +24,31 @@ func main() {
 
 	metalArchivesWrapperConfig, err := config.ReadConfig()
 
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	clientConfig, err := scraper.ReadConfig()
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	metalArchivesClient, err := scraper.NewClientFromConfig(httpClient, clientConfig)
+
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	} else {
 		log.Println("Config readed successfully.")
 
-		jobManagementError := queues.StartJobManagement(metalArchivesWrapperConfig, client)
+		// Shutdown signals cancel in-flight scraping
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		jobManagementError := queues.StartJobManagement(ctx, metalArchivesWrapperConfig, metalArchivesClient, clientConfig.JobTimeout)
 
 		if jobManagementError != nil {
 			fmt.Println(jobManagementError)
@@ -0,0 +1,20 @@
+// +build integration_tests unit_tests
+
+package musicians
+
+import (
+	"net/http"
+)
+
+type RoundTripperMock struct {
+	Response *http.Response
+	RespErr  error
+}
+
+func (rtm *RoundTripperMock) RoundTrip(*http.Request) (*http.Response, error) {
+	// Mocked responses without status are successful ones.
+	if rtm.Response != nil && rtm.Response.StatusCode == 0 {
+		rtm.Response.StatusCode = http.StatusOK
+	}
+	return rtm.Response, rtm.RespErr
+}
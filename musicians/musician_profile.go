@@ -0,0 +1,217 @@
+package musicians
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/artists"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"golang.org/x/net/html"
+)
+
+// Profile fields, used as MusicianProfile Errors keys.
+const (
+	RealNameField = "Real/full name"
+	AgeField      = "Age"
+	OriginField   = "Place of birth"
+	GenderField   = "Gender"
+)
+
+// Release is a release a musician appears on within a band.
+type Release struct {
+	Name  string
+	URL   string
+	ID    string
+	Year  int
+	Roles string
+}
+
+// Membership is a band a musician plays or played in, BandURL and BandID are
+// empty when the band has no page upstream.
+type Membership struct {
+	Band     string
+	BandURL  string
+	BandID   string
+	Roles    []artists.Role
+	Years    []artists.YearsActive
+	Releases []Release
+}
+
+// MusicianProfile holds a musician page. Fields which could not be parsed
+// are left empty and their error is stored in Errors.
+type MusicianProfile struct {
+	Name        string
+	URL         string
+	ID          string
+	RealName    string
+	Age         int
+	Origin      string
+	Gender      string
+	Biography   string
+	ActiveBands []Membership
+	PastBands   []Membership
+	Errors      map[string]error
+}
+
+var (
+	agere       = regexp.MustCompile(`^([0-9]+)`)
+	releaseIDre = regexp.MustCompile(`/albums/[^/]*/[^/]*/([0-9]+)$`)
+)
+
+func (profile *MusicianProfile) fieldError(field string, err error) {
+	if profile.Errors == nil {
+		profile.Errors = make(map[string]error)
+	}
+	profile.Errors[field] = err
+}
+
+func readMembership(band *html.Node) (Membership, error) {
+	var membership Membership
+
+	name := types.FindElement(band, "h3", types.WithClass("member_in_band_name"))
+	if name == nil {
+		return membership, fmt.Errorf("Band membership has no band name.")
+	}
+	membership.Band = types.Text(name)
+	if link := types.FindElement(name, "a", nil); link != nil {
+		membership.BandURL = types.Attribute(link, "href")
+		membership.BandID, _ = types.BandID(membership.BandURL)
+	}
+
+	if role := types.FindElement(band, "p", types.WithClass("member_in_band_role")); role != nil {
+		roles, years, err := artists.ParseRoles(types.Text(role))
+		if err != nil {
+			return membership, err
+		}
+		membership.Roles = roles
+		membership.Years = years
+	}
+
+	for _, row := range types.FindElements(band, "tr", nil) {
+		cells := types.ChildElements(row, "td")
+		if len(cells) < 2 {
+			continue
+		}
+		link := types.FindElement(cells[1], "a", nil)
+		if link == nil {
+			return membership, fmt.Errorf("Release row of '%s' has no release link.", membership.Band)
+		}
+		release := Release{Name: types.Text(link), URL: types.Attribute(link, "href")}
+		if match := releaseIDre.FindStringSubmatch(release.URL); match != nil {
+			release.ID = match[1]
+		}
+		release.Year, _ = strconv.Atoi(types.Text(cells[0]))
+		if len(cells) > 2 {
+			release.Roles = types.Text(cells[2])
+		}
+		membership.Releases = append(membership.Releases, release)
+	}
+
+	return membership, nil
+}
+
+func readMemberships(doc *html.Node, tab string) ([]Membership, error) {
+	var memberships []Membership
+
+	panel := types.FindElement(doc, "div", types.WithID(tab))
+	if panel == nil {
+		return memberships, nil
+	}
+
+	for _, band := range types.FindElements(panel, "div", types.WithClass("member_in_band")) {
+		membership, err := readMembership(band)
+		if err != nil {
+			return memberships, err
+		}
+		memberships = append(memberships, membership)
+	}
+
+	return memberships, nil
+}
+
+func readProfile(doc *html.Node, profile *MusicianProfile) error {
+	info := types.FindElement(doc, "div", types.WithID("member_info"))
+	if info == nil {
+		return fmt.Errorf("Musician page has no member info.")
+	}
+
+	if name := types.FindElement(info, "h1", types.WithClass("band_member_name")); name != nil {
+		profile.Name = types.Text(name)
+	}
+
+	definitions := types.Definitions(info)
+	value := func(field string) (string, bool) {
+		definition, found := definitions[field]
+		if !found {
+			profile.fieldError(field, fmt.Errorf("Member info has no '%s'.", field))
+			return "", false
+		}
+		return types.Text(definition), true
+	}
+
+	if realName, found := value(RealNameField); found {
+		profile.RealName = realName
+	}
+
+	if age, found := value(AgeField); found && age != "N/A" {
+		match := agere.FindStringSubmatch(age)
+		if match == nil {
+			profile.fieldError(AgeField, fmt.Errorf("Age '%s' is not a number.", age))
+		} else {
+			profile.Age, _ = strconv.Atoi(match[1])
+		}
+	}
+
+	if origin, found := value(OriginField); found {
+		profile.Origin = origin
+	}
+
+	if gender, found := value(GenderField); found {
+		profile.Gender = gender
+	}
+
+	if biography := types.FindElement(doc, "div", types.WithClass("band_comment")); biography != nil {
+		profile.Biography = types.Text(biography)
+	}
+
+	var err error
+	profile.ActiveBands, err = readMemberships(doc, "artist_tab_active")
+	if err != nil {
+		return err
+	}
+	profile.PastBands, err = readMemberships(doc, "artist_tab_past")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetMusicianProfile retrieves the page of musician id. Pages without member
+// info or with broken band memberships fail, other fields which cannot be
+// parsed are reported in Errors.
+func GetMusicianProfile(ctx context.Context, client scraper.Client, id string) (MusicianProfile, error) {
+	profile := MusicianProfile{ID: id}
+
+	url := fmt.Sprintf("/artists/_/%s", id)
+	body, err := client.GetWithContext(ctx, scraper.MusicianEndpoint, url)
+	if err != nil {
+		return profile, err
+	}
+	profile.URL = client.URL(url)
+
+	doc, err := html.Parse(strings.NewReader(string(body)))
+	if err != nil {
+		return profile, &types.ParseError{What: "musician page", Err: err}
+	}
+
+	if err := readProfile(doc, &profile); err != nil {
+		return profile, &types.ParseError{What: "musician page", Err: err}
+	}
+
+	return profile, nil
+}
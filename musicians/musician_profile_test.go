@@ -0,0 +1,144 @@
+// +build integration_tests unit_tests
+
+package musicians
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+const ihsahnPage = `
+<div id="member_info">
+	<h1 class="band_member_name">Ihsahn</h1>
+	<dl class="float_left">
+		<dt>Real/full name:</dt>
+		<dd>Vegard Sverre Tveitan</dd>
+		<dt>Age:</dt>
+		<dd>49 (born Oct 10th, 1975)</dd>
+	</dl>
+	<dl class="float_right">
+		<dt>Place of birth:</dt>
+		<dd><a href="https://www.metal-archives.com/lists/NO">Norway</a> (Notodden, Telemark)</dd>
+		<dt>Gender:</dt>
+		<dd>Male</dd>
+	</dl>
+</div>
+<div class="band_comment clear">
+	Ihsahn is a Norwegian musician, best known as the founder of Emperor.
+</div>
+<div id="artist_tab_active">
+	<div class="member_in_band" id="memberInBand_30">
+		<h3 class="member_in_band_name"><a href="https://www.metal-archives.com/bands/Emperor/30">Emperor</a></h3>
+		<p class="member_in_band_role">Guitars, Vocals (1991-2001, 2005-present)</p>
+		<table>
+			<tr>
+				<td>1994</td>
+				<td><a href="https://www.metal-archives.com/albums/Emperor/In_the_Nightside_Eclipse/203">In the Nightside Eclipse</a></td>
+				<td>Vocals, Guitars, Keyboards</td>
+			</tr>
+			<tr>
+				<td>1997</td>
+				<td><a href="https://www.metal-archives.com/albums/Emperor/Anthems_to_the_Welkin_at_Dusk/204">Anthems to the Welkin at Dusk</a></td>
+				<td>Vocals, Guitars</td>
+			</tr>
+		</table>
+	</div>
+</div>
+<div id="artist_tab_past">
+	<div class="member_in_band" id="memberInBand_1425">
+		<h3 class="member_in_band_name"><a href="https://www.metal-archives.com/bands/Thou_Shalt_Suffer/1425">Thou Shalt Suffer</a></h3>
+		<p class="member_in_band_role">Guitars, Keyboards (1991-1997)</p>
+	</div>
+	<div class="member_in_band">
+		<h3 class="member_in_band_name">Xerasia</h3>
+		<p class="member_in_band_role">Guitars (1989-1990)</p>
+	</div>
+</div>
+`
+
+func TestGetMusicianProfile(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(ihsahnPage))}}})
+
+	profile, err := GetMusicianProfile(context.Background(), client, "1424")
+
+	if err != nil {
+		t.Fatalf("GetMusicianProfile shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(profile.Errors) != 0 {
+		t.Errorf("Every field should be parsed, errors were '%v'.", profile.Errors)
+	}
+
+	if profile.Name != "Ihsahn" || profile.ID != "1424" || profile.URL != "https://www.metal-archives.com/artists/_/1424" {
+		t.Errorf("Profile should be Ihsahn's one, not '%s' with ID '%s' and URL '%s'.", profile.Name, profile.ID, profile.URL)
+	}
+
+	if profile.RealName != "Vegard Sverre Tveitan" || profile.Age != 49 || profile.Gender != "Male" || profile.Origin != "Norway (Notodden, Telemark)" {
+		t.Errorf("Ihsahn stats are wrong, found '%s', %d, '%s' and '%s'.", profile.RealName, profile.Age, profile.Gender, profile.Origin)
+	}
+
+	if profile.Biography != "Ihsahn is a Norwegian musician, best known as the founder of Emperor." {
+		t.Errorf("Ihsahn biography is wrong, found '%s'.", profile.Biography)
+	}
+
+	if len(profile.ActiveBands) != 1 {
+		t.Fatalf("Ihsahn should have 1 active band, not %d.", len(profile.ActiveBands))
+	}
+
+	emperor := profile.ActiveBands[0]
+	if emperor.Band != "Emperor" || emperor.BandID != "30" || len(emperor.Roles) != 2 || len(emperor.Years) != 2 || !emperor.Years[1].Present {
+		t.Errorf("Ihsahn active band should be Emperor since 2005, not '%v'.", emperor)
+	}
+
+	if len(emperor.Releases) != 2 || emperor.Releases[0] != (Release{Name: "In the Nightside Eclipse", URL: "https://www.metal-archives.com/albums/Emperor/In_the_Nightside_Eclipse/203", ID: "203", Year: 1994, Roles: "Vocals, Guitars, Keyboards"}) {
+		t.Errorf("Emperor releases are wrong, found '%v'.", emperor.Releases)
+	}
+
+	if len(profile.PastBands) != 2 || profile.PastBands[0].BandID != "1425" || profile.PastBands[1].Band != "Xerasia" || profile.PastBands[1].BandURL != "" {
+		t.Errorf("Ihsahn past bands should be Thou Shalt Suffer and Xerasia without page, not '%v'.", profile.PastBands)
+	}
+}
+
+func TestGetMusicianProfileFieldErrors(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`
+<div id="member_info">
+	<h1 class="band_member_name">Someone</h1>
+	<dl>
+		<dt>Age:</dt><dd>unknown</dd>
+		<dt>Gender:</dt><dd>Female</dd>
+	</dl>
+</div>
+`))}}})
+
+	profile, err := GetMusicianProfile(context.Background(), client, "1")
+
+	if err != nil {
+		t.Fatalf("GetMusicianProfile shouldn't fail on broken fields, error was '%s'.", err.Error())
+	}
+
+	for _, field := range []string{RealNameField, AgeField, OriginField} {
+		if profile.Errors[field] == nil {
+			t.Errorf("'%s' field should have an error.", field)
+		}
+	}
+
+	if profile.Errors[GenderField] != nil || profile.Gender != "Female" {
+		t.Errorf("Gender should be parsed as 'Female', not '%s'.", profile.Gender)
+	}
+}
+
+func TestGetMusicianProfileWithoutInfo(t *testing.T) {
+	client := scraper.NewClient(http.Client{Transport: &RoundTripperMock{Response: &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`<html><body>Something else</body></html>`))}}})
+
+	_, err := GetMusicianProfile(context.Background(), client, "1")
+
+	if !errors.Is(err, types.ErrParse) {
+		t.Errorf("Pages without member info should return ErrParse, not '%v'.", err)
+	}
+}
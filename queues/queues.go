@@ -1,15 +1,24 @@
 package queues
 
 import (
+	"context"
 	"fmt"
 	config "github.com/a-castellano/music-manager-config-reader/config_reader"
 	"github.com/a-castellano/music-manager-metal-archives-wrapper/jobs"
+	"github.com/a-castellano/music-manager-metal-archives-wrapper/scraper"
 	"github.com/streadway/amqp"
-	"net/http"
 	"strconv"
+	"time"
 )
 
-func StartJobManagement(config config.Config, client http.Client) error {
+// consumerTag identifies the jobs consumer so it can be cancelled on shutdown.
+const consumerTag = "music-manager-metal-archives-wrapper"
+
+// StartJobManagement consumes jobs until a Die job is received or ctx is
+// cancelled, in which case the job being processed is stopped and requeued.
+// Each job is stopped after jobTimeout, 0 means no timeout, and its failure
+// is sent as any other job result.
+func StartJobManagement(ctx context.Context, config config.Config, client scraper.Client, jobTimeout time.Duration) error {
 
 	connection_string := "amqp://" + config.Server.User + ":" + config.Server.Password + "@" + config.Server.Host + ":" + strconv.Itoa(config.Server.Port) + "/"
 	conn, err := amqp.Dial(connection_string)
@@ -71,7 +80,7 @@ func StartJobManagement(config config.Config, client http.Client) error {
 
 	jobsToProcess, err := incoming_ch.Consume(
 		incoming_q.Name,
-		"",    // consumer
+		consumerTag,
 		false, // auto-ack
 		false, // exclusive
 		false, // no-local
@@ -83,19 +92,33 @@ func StartJobManagement(config config.Config, client http.Client) error {
 		return fmt.Errorf("Failed to register a consumer: %w", err)
 	}
 
-	processJobs := make(chan bool)
+	processJobs := make(chan struct{})
+	var publishErr error
 
 	go func() {
+		defer close(processJobs)
 		for job := range jobsToProcess {
 
-			die, jobResult, _ := jobs.ProcessJob(job.Body, config.Origin, client)
+			var jobCtx context.Context
+			var cancelJob context.CancelFunc
+			if jobTimeout > 0 {
+				jobCtx, cancelJob = context.WithTimeout(ctx, jobTimeout)
+			} else {
+				jobCtx, cancelJob = context.WithCancel(ctx)
+			}
+			die, jobResult, _ := jobs.ProcessJob(jobCtx, job.Body, config.Origin, client)
+			cancelJob()
+
+			if ctx.Err() != nil {
+				job.Nack(false, true)
+				return
+			}
 
 			if die {
 				job.Ack(false)
-				processJobs <- false
 				return
 			}
-			err = outgoing_ch.Publish(
+			publishErr = outgoing_ch.Publish(
 				"",              // exchange
 				outgoing_q.Name, // routing key
 				false,           // mandatory
@@ -105,17 +128,26 @@ func StartJobManagement(config config.Config, client http.Client) error {
 					ContentType:  "text/plain",
 					Body:         jobResult,
 				})
-			if err != nil {
-				fmt.Println(err)
+			if publishErr != nil {
+				job.Nack(false, true)
 				return
 			}
 
 			job.Ack(false)
 		}
-		return
 	}()
 
-	<-processJobs
+	// Channels are closed on return, jobs must be acknowledged before.
+	select {
+	case <-processJobs:
+	case <-ctx.Done():
+		incoming_ch.Cancel(consumerTag, false)
+		<-processJobs
+	}
+
+	if publishErr != nil {
+		return fmt.Errorf("Failed to publish job result: %w", publishErr)
+	}
 
 	return nil
 }
@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+)
+
+// AjaxPageLength is the amount of rows requested on each page of DataTables
+// endpoints, upstream does not return more than 200 rows per page.
+const AjaxPageLength = 200
+
+// DefaultMaxResults caps how many rows are collected from paginated endpoints.
+const DefaultMaxResults = 1000
+
+// pageURL adds DataTables paging parameters to url.
+func pageURL(url string, start int, length int) string {
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%siDisplayStart=%d&iDisplayLength=%d", url, separator, start, length)
+}
+
+// FetchAjaxPage retrieves the page of a DataTables endpoint starting at row start.
+func (c Client) FetchAjaxPage(ctx context.Context, endpoint Endpoint, url string, start int, length int) (types.SearchAjaxData, error) {
+	var page types.SearchAjaxData
+
+	body, err := c.GetWithContext(ctx, endpoint, pageURL(url, start, length))
+	if err != nil {
+		return page, err
+	}
+
+	jsonErr := json.Unmarshal(body, &page)
+	if jsonErr != nil {
+		return page, &types.ParseError{What: url, Err: jsonErr}
+	}
+
+	return page, nil
+}
+
+// FetchAjaxPages walks a DataTables endpoint from row start until every row
+// is collected or maxRecords rows are found, maxRecords lower than 1 means no
+// cap. Returned TotalRecords and TotalDisplayRecords are the upstream ones.
+func (c Client) FetchAjaxPages(ctx context.Context, endpoint Endpoint, url string, start int, maxRecords int) (types.SearchAjaxData, error) {
+	var pages types.SearchAjaxData
+
+	for {
+		length := AjaxPageLength
+		if maxRecords > 0 && maxRecords-len(pages.Data) < length {
+			length = maxRecords - len(pages.Data)
+		}
+
+		page, err := c.FetchAjaxPage(ctx, endpoint, url, start, length)
+		if err != nil {
+			return pages, err
+		}
+
+		pages.Error = page.Error
+		pages.Echo = page.Echo
+		pages.TotalRecords = page.TotalRecords
+		pages.TotalDisplayRecords = page.TotalDisplayRecords
+		pages.Data = append(pages.Data, page.Data...)
+		start += len(page.Data)
+
+		if len(page.Data) < length || start >= page.TotalDisplayRecords {
+			break
+		}
+		if maxRecords > 0 && len(pages.Data) >= maxRecords {
+			break
+		}
+	}
+
+	return pages, nil
+}
@@ -0,0 +1,106 @@
+// +build integration_tests unit_tests
+
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newAjaxServer serves totalRecords rows paginated as upstream does.
+func newAjaxServer(totalRecords int, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		start, _ := strconv.Atoi(r.URL.Query().Get("iDisplayStart"))
+		length, _ := strconv.Atoi(r.URL.Query().Get("iDisplayLength"))
+
+		page := types.SearchAjaxData{TotalRecords: totalRecords, TotalDisplayRecords: totalRecords, Data: [][]string{}}
+		for row := start; row < start+length && row < totalRecords; row++ {
+			page.Data = append(page.Data, []string{fmt.Sprintf("row %d", row)})
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+}
+
+func TestPageURL(t *testing.T) {
+	if pageURL("/search/ajax-band-search/?field=name&query=Abyss", 200, 200) != "/search/ajax-band-search/?field=name&query=Abyss&iDisplayStart=200&iDisplayLength=200" {
+		t.Errorf("Paging parameters should be appended to existing query, got '%s'.", pageURL("/search/ajax-band-search/?field=name&query=Abyss", 200, 200))
+	}
+
+	if pageURL("/browse/ajax-letter/l/A/json/1", 0, 500) != "/browse/ajax-letter/l/A/json/1?iDisplayStart=0&iDisplayLength=500" {
+		t.Errorf("Paging parameters should start a query, got '%s'.", pageURL("/browse/ajax-letter/l/A/json/1", 0, 500))
+	}
+}
+
+func TestFetchAjaxPagesWalksEveryPage(t *testing.T) {
+	requests := 0
+	server := newAjaxServer(450, &requests)
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	data, err := client.FetchAjaxPages(context.Background(), SearchEndpoint, "/search/ajax-band-search/?field=name&query=Abyss", 0, 0)
+
+	if err != nil {
+		t.Fatalf("FetchAjaxPages shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(data.Data) != 450 || data.TotalRecords != 450 {
+		t.Errorf("FetchAjaxPages should collect 450 rows, it collected %d of %d.", len(data.Data), data.TotalRecords)
+	}
+
+	if data.Data[449][0] != "row 449" {
+		t.Errorf("Last row should be 'row 449', not '%s'.", data.Data[449][0])
+	}
+
+	if requests != 3 {
+		t.Errorf("FetchAjaxPages should request 3 pages, it requested %d.", requests)
+	}
+}
+
+func TestFetchAjaxPagesCap(t *testing.T) {
+	requests := 0
+	server := newAjaxServer(450, &requests)
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	data, err := client.FetchAjaxPages(context.Background(), SearchEndpoint, "/search/ajax-band-search/?field=name&query=Abyss", 100, 250)
+
+	if err != nil {
+		t.Fatalf("FetchAjaxPages shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(data.Data) != 250 || data.TotalRecords != 450 {
+		t.Errorf("FetchAjaxPages should collect 250 rows and report 450, it collected %d and reported %d.", len(data.Data), data.TotalRecords)
+	}
+
+	if data.Data[0][0] != "row 100" {
+		t.Errorf("First row should be 'row 100', not '%s'.", data.Data[0][0])
+	}
+}
+
+func TestFetchAjaxPageBrokenJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"aaData": [`))
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	_, err := client.FetchAjaxPages(context.Background(), SearchEndpoint, "/", 0, 0)
+
+	if !errors.Is(err, types.ErrParse) {
+		t.Errorf("Broken JSON should return ErrParse, not '%v'.", err)
+	}
+}
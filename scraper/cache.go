@@ -0,0 +1,215 @@
+package scraper
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Endpoint identifies the kind of upstream page requested, cache TTL depends on it.
+type Endpoint string
+
+const (
+	SearchEndpoint      Endpoint = "search"
+	DiscographyEndpoint Endpoint = "discography"
+	AlbumEndpoint       Endpoint = "album"
+	BandEndpoint        Endpoint = "band"
+	MusicianEndpoint    Endpoint = "musician"
+	BrowseEndpoint      Endpoint = "browse"
+	LatestEndpoint      Endpoint = "latest"
+)
+
+// DefaultCacheTTL keeps search results for a short time while album pages,
+// which rarely change, are kept longer.
+func DefaultCacheTTL() map[Endpoint]time.Duration {
+	return map[Endpoint]time.Duration{
+		SearchEndpoint:      time.Hour,
+		DiscographyEndpoint: 12 * time.Hour,
+		AlbumEndpoint:       7 * 24 * time.Hour,
+		BandEndpoint:        24 * time.Hour,
+		MusicianEndpoint:    7 * 24 * time.Hour,
+		BrowseEndpoint:      24 * time.Hour,
+		LatestEndpoint:      time.Hour,
+	}
+}
+
+func (c Client) cacheTTL(endpoint Endpoint) time.Duration {
+	if ttl, found := c.CacheTTL[endpoint]; found {
+		return ttl
+	}
+	return DefaultCacheTTL()[endpoint]
+}
+
+// Cache stores raw upstream responses.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Stats() CacheStats
+}
+
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// CacheStats returns client cache counters, they are empty when client has no cache.
+func (c Client) CacheStats() CacheStats {
+	if c.Cache == nil {
+		return CacheStats{}
+	}
+	return c.Cache.Stats()
+}
+
+type cacheCounters struct {
+	hits   int64
+	misses int64
+}
+
+func (counters *cacheCounters) hit() {
+	atomic.AddInt64(&counters.hits, 1)
+}
+
+func (counters *cacheCounters) miss() {
+	atomic.AddInt64(&counters.misses, 1)
+}
+
+func (counters *cacheCounters) Stats() CacheStats {
+	return CacheStats{Hits: atomic.LoadInt64(&counters.hits), Misses: atomic.LoadInt64(&counters.misses)}
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// MemoryCache is a least recently used cache holding up to Size responses.
+type MemoryCache struct {
+	cacheCounters
+	mutex   sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func NewMemoryCache(size int) *MemoryCache {
+	if size < 1 {
+		size = 1
+	}
+	return &MemoryCache{size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+func (cache *MemoryCache) Get(key string) ([]byte, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, found := cache.entries[key]
+	if !found {
+		cache.miss()
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryCacheEntry)
+	if time.Now().After(entry.expires) {
+		cache.order.Remove(element)
+		delete(cache.entries, key)
+		cache.miss()
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+	cache.hit()
+	return entry.value, true
+}
+
+func (cache *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, found := cache.entries[key]; found {
+		entry := element.Value.(*memoryCacheEntry)
+		entry.value = value
+		entry.expires = time.Now().Add(ttl)
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.entries[key] = cache.order.PushFront(&memoryCacheEntry{key: key, value: value, expires: time.Now().Add(ttl)})
+
+	for cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// DiskCache stores each response in its own file inside Dir, the first line
+// of every file holds its expiration time.
+type DiskCache struct {
+	cacheCounters
+	Dir string
+}
+
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{Dir: dir}, nil
+}
+
+func (cache *DiskCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(cache.Dir, hex.EncodeToString(hash[:]))
+}
+
+func (cache *DiskCache) Get(key string) ([]byte, bool) {
+	content, err := ioutil.ReadFile(cache.path(key))
+	if err != nil {
+		cache.miss()
+		return nil, false
+	}
+
+	splitted := strings.SplitN(string(content), "\n", 2)
+	if len(splitted) != 2 {
+		cache.miss()
+		return nil, false
+	}
+
+	expires, err := strconv.ParseInt(splitted[0], 10, 64)
+	if err != nil || time.Now().UnixNano() > expires {
+		os.Remove(cache.path(key))
+		cache.miss()
+		return nil, false
+	}
+
+	cache.hit()
+	return []byte(splitted[1]), true
+}
+
+func (cache *DiskCache) Set(key string, value []byte, ttl time.Duration) {
+	expires := strconv.FormatInt(time.Now().Add(ttl).UnixNano(), 10)
+	content := append([]byte(expires+"\n"), value...)
+
+	// Files are written aside and renamed so readers never see partial content.
+	tmpFile, err := ioutil.TempFile(cache.Dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmpFile.Write(content)
+	closeErr := tmpFile.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmpFile.Name())
+		return
+	}
+	if err := os.Rename(tmpFile.Name(), cache.path(key)); err != nil {
+		os.Remove(tmpFile.Name())
+	}
+}
@@ -0,0 +1,138 @@
+// +build integration_tests unit_tests
+
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheHitsAndMisses(t *testing.T) {
+	cache := NewMemoryCache(10)
+
+	if _, found := cache.Get("key"); found {
+		t.Errorf("Empty cache shouldn't contain 'key'.")
+	}
+
+	cache.Set("key", []byte("value"), time.Hour)
+
+	value, found := cache.Get("key")
+	if !found || string(value) != "value" {
+		t.Errorf("Cache should return 'value' for 'key'.")
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Cache should count 1 hit and 1 miss, not %d and %d.", stats.Hits, stats.Misses)
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	cache.Set("first", []byte("1"), time.Hour)
+	cache.Set("second", []byte("2"), time.Hour)
+	cache.Get("first")
+	cache.Set("third", []byte("3"), time.Hour)
+
+	if _, found := cache.Get("second"); found {
+		t.Errorf("'second' was the least recently used entry and should be evicted.")
+	}
+
+	if _, found := cache.Get("first"); !found {
+		t.Errorf("'first' was recently used and should be kept.")
+	}
+}
+
+func TestMemoryCacheExpiration(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	cache.Set("key", []byte("value"), -time.Second)
+
+	if _, found := cache.Get("key"); found {
+		t.Errorf("Expired entries shouldn't be returned.")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	cache.Set("key", []byte("first line\nsecond line"), time.Hour)
+	cache.Set("expired", []byte("value"), -time.Second)
+
+	value, found := cache.Get("key")
+	if !found || string(value) != "first line\nsecond line" {
+		t.Errorf("Disk cache should return stored value, got '%s'.", string(value))
+	}
+
+	if _, found := cache.Get("expired"); found {
+		t.Errorf("Expired entries shouldn't be returned.")
+	}
+
+	if _, found := cache.Get("missing"); found {
+		t.Errorf("Missing entries shouldn't be returned.")
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("Cache should count 1 hit and 2 misses, not %d and %d.", stats.Hits, stats.Misses)
+	}
+}
+
+func TestFetchUsesCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.Cache = NewMemoryCache(10)
+
+	client.Get(SearchEndpoint, "/search")
+	response, err := client.Fetch(SearchEndpoint, "/search")
+
+	if err != nil {
+		t.Errorf("Fetch shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if !response.Cached || string(response.Body) != "body" {
+		t.Errorf("Second request should be served from cache.")
+	}
+
+	if requests != 1 {
+		t.Errorf("Upstream should receive only one request, it received %d.", requests)
+	}
+
+	stats := client.CacheStats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Client cache should count 1 hit and 1 miss, not %d and %d.", stats.Hits, stats.Misses)
+	}
+}
+
+func TestFetchDoesNotCacheErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.Cache = NewMemoryCache(10)
+
+	client.Get(AlbumEndpoint, "/albums/missing")
+	client.Get(AlbumEndpoint, "/albums/missing")
+
+	if requests != 2 {
+		t.Errorf("Failed responses shouldn't be cached, upstream received %d requests.", requests)
+	}
+}
@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"net/http"
+	"strings"
+
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+)
+
+// Markers found in Cloudflare interstitials, they are specific enough to be
+// looked for in any response.
+var challengeMarkers = []string{
+	"cf-browser-verification",
+	"<title>Just a moment...</title>",
+	"<title>Attention Required! | Cloudflare</title>",
+}
+
+// Challenge scripts, Cloudflare also adds them to normal pages of protected
+// sites so they are only trusted on challenge statuses.
+var challengeScriptMarkers = []string{
+	"cf_chl_opt",
+	"/cdn-cgi/challenge-platform/",
+}
+
+// Markers only trusted on error responses, normal pages could contain them.
+var captchaMarkers = []string{
+	"g-recaptcha",
+	"h-captcha",
+	"cf-turnstile",
+}
+
+var maintenanceMarkers = []string{
+	"down for maintenance",
+	"under maintenance",
+	"maintenance mode",
+}
+
+// blockedPage tells if response is a challenge, captcha or maintenance page
+// instead of the requested content.
+func blockedPage(response Response) (types.BlockedPage, bool) {
+	if strings.EqualFold(response.Header.Get("Cf-Mitigated"), "challenge") {
+		return types.ChallengePage, true
+	}
+
+	body := strings.ToLower(string(response.Body))
+
+	for _, marker := range challengeMarkers {
+		if strings.Contains(body, strings.ToLower(marker)) {
+			return types.ChallengePage, true
+		}
+	}
+
+	challenged := response.StatusCode == http.StatusForbidden || response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusServiceUnavailable
+	if challenged {
+		for _, marker := range challengeScriptMarkers {
+			if strings.Contains(body, marker) {
+				return types.ChallengePage, true
+			}
+		}
+	}
+
+	failed := response.StatusCode == http.StatusForbidden || response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+	if !failed {
+		return "", false
+	}
+
+	for _, marker := range captchaMarkers {
+		if strings.Contains(body, marker) {
+			return types.CaptchaPage, true
+		}
+	}
+
+	for _, marker := range maintenanceMarkers {
+		if strings.Contains(body, marker) {
+			return types.MaintenancePage, true
+		}
+	}
+
+	// Cloudflare error pages without any known marker
+	if response.StatusCode == http.StatusForbidden && strings.EqualFold(response.Header.Get("Server"), "cloudflare") {
+		return types.ChallengePage, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,131 @@
+// +build integration_tests unit_tests
+
+package scraper
+
+import (
+	"errors"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBlockedPageCloudflareChallenge(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Server", "cloudflare")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`<!DOCTYPE html><html><head><title>Just a moment...</title></head><body><div id="cf-browser-verification"></div></body></html>`))
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	_, err := client.Fetch(SearchEndpoint, "/")
+
+	var blockedErr *types.BlockedError
+	if !errors.As(err, &blockedErr) || blockedErr.Page != types.ChallengePage {
+		t.Fatalf("Fetch should return a challenge BlockedError, not '%v'.", err)
+	}
+
+	if !errors.Is(err, types.ErrBlocked) {
+		t.Errorf("Challenge pages should be ErrBlocked.")
+	}
+
+	if requests != 1 {
+		t.Errorf("Challenge pages shouldn't be retried, upstream received %d requests.", requests)
+	}
+}
+
+func TestBlockedPageChallengeScriptOnNormalPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "cloudflare")
+		w.Write([]byte(`<html><head><title>Burzum - Encyclopaedia Metallum</title></head><body><div id="band_stats"></div><script src="/cdn-cgi/challenge-platform/scripts/jsd/main.js"></script><script>window.__CF$cv$params={r:'1',t:'2'};</script></body></html>`))
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	response, err := client.Fetch(BandEndpoint, "/bands/_/88")
+
+	if err != nil {
+		t.Fatalf("Normal pages with Cloudflare scripts shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Normal page status should be 200, not %d.", response.StatusCode)
+	}
+}
+
+func TestBlockedPageChallengeScriptOnChallengeStatus(t *testing.T) {
+	body := []byte(`<html><body><script>window._cf_chl_opt={cvId: '3'};</script><script src="/cdn-cgi/challenge-platform/h/b/orchestrate/chl_page/v1"></script></body></html>`)
+
+	page, blocked := blockedPage(Response{StatusCode: http.StatusForbidden, Header: http.Header{}, Body: body})
+	if !blocked || page != types.ChallengePage {
+		t.Errorf("Forbidden responses with challenge scripts should be challenge pages.")
+	}
+
+	if _, blocked := blockedPage(Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: body}); blocked {
+		t.Errorf("Successful responses with challenge scripts alone shouldn't be challenge pages.")
+	}
+}
+
+func TestBlockedPageMitigatedHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("Cf-Mitigated", "challenge")
+
+	page, blocked := blockedPage(Response{StatusCode: http.StatusForbidden, Header: header})
+
+	if !blocked || page != types.ChallengePage {
+		t.Errorf("Responses with 'cf-mitigated: challenge' header should be challenge pages.")
+	}
+}
+
+func TestBlockedPageCaptcha(t *testing.T) {
+	page, blocked := blockedPage(Response{StatusCode: http.StatusForbidden, Header: http.Header{}, Body: []byte(`<form><div class="g-recaptcha" data-sitekey="key"></div></form>`)})
+
+	if !blocked || page != types.CaptchaPage {
+		t.Errorf("Forbidden responses with a recaptcha should be captcha pages.")
+	}
+
+	if _, blocked := blockedPage(Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: []byte(`<form><div class="g-recaptcha"></div></form>`)}); blocked {
+		t.Errorf("Successful responses containing a recaptcha form are regular pages.")
+	}
+}
+
+func TestBlockedPageMaintenance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`<html><body><h1>The Metal Archives is down for maintenance.</h1></body></html>`))
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	_, err := client.Fetch(AlbumEndpoint, "/")
+
+	var blockedErr *types.BlockedError
+	if !errors.As(err, &blockedErr) || blockedErr.Page != types.MaintenancePage {
+		t.Fatalf("Fetch should return a maintenance BlockedError, not '%v'.", err)
+	}
+
+	if !errors.Is(err, types.ErrUpstreamUnavailable) {
+		t.Errorf("Maintenance pages should be ErrUpstreamUnavailable too.")
+	}
+}
+
+func TestBlockedPageRegularPage(t *testing.T) {
+	if _, blocked := blockedPage(Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: []byte(`<html><head><title>Burzum - Encyclopaedia Metallum: The Metal Archives</title></head></html>`)}); blocked {
+		t.Errorf("Regular pages shouldn't be blocked pages.")
+	}
+
+	if _, blocked := blockedPage(Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: []byte(`Not found`)}); blocked {
+		t.Errorf("Not found pages shouldn't be blocked pages.")
+	}
+}
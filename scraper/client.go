@@ -0,0 +1,246 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the site scraped when no other base URL is configured.
+const DefaultBaseURL = "https://www.metal-archives.com"
+
+// DefaultUserAgent identifies this wrapper against upstream.
+const DefaultUserAgent = "https://github.com/a-castellano/metal-archives-wrapper"
+
+// Client is the single entry point used by every scraper to reach Metal Archives.
+type Client struct {
+	HTTPClient http.Client
+	BaseURL    string
+	UserAgent  string
+	Headers    http.Header
+	Limiter    *RateLimiter
+	Retry      RetryPolicy
+	Cache      Cache
+	CacheTTL   map[Endpoint]time.Duration
+	MaxResults int
+	MatchMode  types.MatchMode
+}
+
+// Response is the outcome of a request made through Client.
+type Response struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Waited     time.Duration
+	Attempts   int
+	Cached     bool
+}
+
+// invalidURLError flags requests that could not be built, retrying them is pointless.
+type invalidURLError struct {
+	error
+}
+
+func NewClient(httpClient http.Client) Client {
+	return Client{
+		HTTPClient: httpClient,
+		BaseURL:    DefaultBaseURL,
+		UserAgent:  DefaultUserAgent,
+		Headers:    http.Header{},
+		Retry:      DefaultRetryPolicy,
+		CacheTTL:   DefaultCacheTTL(),
+		MaxResults: DefaultMaxResults,
+		MatchMode:  types.NormalizedMatch,
+	}
+}
+
+// URL returns the absolute URL for a site path. Absolute Metal Archives URLs,
+// like the ones found inside scraped pages, are moved to the configured base URL.
+func (c Client) URL(path string) string {
+	baseURL := strings.TrimRight(c.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	for _, prefix := range []string{DefaultBaseURL, "http://www.metal-archives.com"} {
+		if strings.HasPrefix(path, prefix) {
+			return baseURL + strings.TrimPrefix(path, prefix)
+		}
+	}
+
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+
+	return baseURL + "/" + strings.TrimLeft(path, "/")
+}
+
+func (c Client) newRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL(url), nil)
+	if err != nil {
+		return req, invalidURLError{err}
+	}
+
+	for header, values := range c.Headers {
+		for _, value := range values {
+			req.Header.Add(header, value)
+		}
+	}
+
+	userAgent := c.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	return req, nil
+}
+
+// do sends a single request for url after waiting for the client rate limiter, if any.
+func (c Client) do(ctx context.Context, url string) (Response, error) {
+	var response Response
+
+	req, err := c.newRequest(ctx, url)
+	if err != nil {
+		return response, err
+	}
+	response.URL = req.URL.String()
+
+	if c.Limiter != nil {
+		response.Waited, err = c.Limiter.Wait(ctx)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	res, getErr := c.HTTPClient.Do(req)
+	if getErr != nil {
+		// Cancelled requests are not upstream failures.
+		if ctx.Err() != nil {
+			return response, ctx.Err()
+		}
+		return response, &types.UnavailableError{Err: getErr}
+	}
+	if res.Body == nil {
+		return response, &types.UnavailableError{Err: fmt.Errorf("Empty response received from %s.", response.URL)}
+	}
+	defer res.Body.Close()
+
+	response.StatusCode = res.StatusCode
+	response.Header = res.Header
+
+	body, readErr := ioutil.ReadAll(res.Body)
+	if readErr != nil {
+		if ctx.Err() != nil {
+			return response, ctx.Err()
+		}
+		return response, &types.UnavailableError{Err: readErr}
+	}
+	response.Body = body
+
+	return response, nil
+}
+
+// fetchWithRetries retries network errors and retryable status codes following
+// client Retry policy, any other non successful status is returned as a
+// *types.StatusError and challenge or maintenance pages as a *types.BlockedError.
+func (c Client) fetchWithRetries(ctx context.Context, url string) (Response, error) {
+	var response Response
+	var err error
+	var waited time.Duration
+
+	attempts := 0
+	for {
+		attempts++
+		response, err = c.do(ctx, url)
+		waited += response.Waited
+		response.Waited = waited
+		response.Attempts = attempts
+
+		if _, invalidURL := err.(invalidURLError); invalidURL || ctx.Err() != nil {
+			return response, err
+		}
+
+		// Retrying won't get through a challenge or maintenance page.
+		if err == nil {
+			if page, blocked := blockedPage(response); blocked {
+				return response, &types.BlockedError{Page: page, StatusCode: response.StatusCode, URL: response.URL}
+			}
+		}
+
+		if err == nil && !retryableStatus(response.StatusCode) {
+			if response.StatusCode < 200 || response.StatusCode > 299 {
+				return response, &types.StatusError{StatusCode: response.StatusCode, URL: response.URL}
+			}
+			return response, nil
+		}
+
+		if err == nil {
+			err = &types.StatusError{StatusCode: response.StatusCode, URL: response.URL}
+		}
+
+		if attempts >= c.Retry.MaxAttempts {
+			return response, &RetryError{Attempts: attempts, Err: err}
+		}
+
+		delay, found := retryAfter(response.Header, time.Now())
+		if !found {
+			delay = c.Retry.backoff(attempts)
+		} else if c.Retry.MaxDelay > 0 && delay > c.Retry.MaxDelay {
+			// Waiting longer than MaxDelay would stall callers without deadline,
+			// the error keeps the status which asked for the wait.
+			return response, &RetryError{Attempts: attempts, Err: err}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return response, ctx.Err()
+		}
+	}
+}
+
+// Fetch retrieves url, which can be a site path or an absolute URL. Successful
+// responses are stored in client cache, if any, using endpoint TTL.
+func (c Client) Fetch(endpoint Endpoint, url string) (Response, error) {
+	return c.FetchWithContext(context.Background(), endpoint, url)
+}
+
+// FetchWithContext is Fetch stopping as soon as ctx is cancelled, including
+// rate limiter and retry waits.
+func (c Client) FetchWithContext(ctx context.Context, endpoint Endpoint, url string) (Response, error) {
+	key := c.URL(url)
+
+	if c.Cache != nil {
+		if body, found := c.Cache.Get(key); found {
+			return Response{URL: key, StatusCode: http.StatusOK, Body: body, Cached: true}, nil
+		}
+	}
+
+	response, err := c.fetchWithRetries(ctx, url)
+
+	if err == nil && c.Cache != nil {
+		if ttl := c.cacheTTL(endpoint); ttl > 0 {
+			c.Cache.Set(key, response.Body, ttl)
+		}
+	}
+
+	return response, err
+}
+
+// Get retrieves url and returns its body.
+func (c Client) Get(endpoint Endpoint, url string) ([]byte, error) {
+	return c.GetWithContext(context.Background(), endpoint, url)
+}
+
+func (c Client) GetWithContext(ctx context.Context, endpoint Endpoint, url string) ([]byte, error) {
+	response, err := c.FetchWithContext(ctx, endpoint, url)
+	return response.Body, err
+}
@@ -0,0 +1,108 @@
+// +build integration_tests unit_tests
+
+package scraper
+
+import (
+	"context"
+	"errors"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestURLWithDefaultBaseURL(t *testing.T) {
+	client := NewClient(http.Client{})
+
+	if client.URL("/bands/Burzum/88") != "https://www.metal-archives.com/bands/Burzum/88" {
+		t.Errorf("URL should be 'https://www.metal-archives.com/bands/Burzum/88', not '%s'.", client.URL("/bands/Burzum/88"))
+	}
+}
+
+func TestURLRewritesMetalArchivesURLs(t *testing.T) {
+	client := NewClient(http.Client{})
+	client.BaseURL = "http://localhost:8080/"
+
+	if client.URL("https://www.metal-archives.com/albums/Burzum/Filosofem/3") != "http://localhost:8080/albums/Burzum/Filosofem/3" {
+		t.Errorf("URL should be rewritten to local base URL, not '%s'.", client.URL("https://www.metal-archives.com/albums/Burzum/Filosofem/3"))
+	}
+
+	if client.URL("band/discography/id/88/tab/all") != "http://localhost:8080/band/discography/id/88/tab/all" {
+		t.Errorf("Relative paths should be joined to local base URL, not '%s'.", client.URL("band/discography/id/88/tab/all"))
+	}
+
+	if client.URL("https://example.org/cover.jpg") != "https://example.org/cover.jpg" {
+		t.Errorf("Foreign URLs should not be rewritten, got '%s'.", client.URL("https://example.org/cover.jpg"))
+	}
+}
+
+func TestGetUsesBaseURLAndHeaders(t *testing.T) {
+	var requestedPath, userAgent, extraHeader string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.RequestURI()
+		userAgent = r.Header.Get("User-Agent")
+		extraHeader = r.Header.Get("X-Test")
+		w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.UserAgent = "TestAgent"
+	client.Headers.Set("X-Test", "test")
+
+	body, err := client.Get(SearchEndpoint, "/search/ajax-band-search/?field=name&query=Burzum")
+
+	if err != nil {
+		t.Errorf("Get shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if string(body) != "body" {
+		t.Errorf("Body should be 'body', not '%s'.", string(body))
+	}
+
+	if requestedPath != "/search/ajax-band-search/?field=name&query=Burzum" {
+		t.Errorf("Requested path should be '/search/ajax-band-search/?field=name&query=Burzum', not '%s'.", requestedPath)
+	}
+
+	if userAgent != "TestAgent" {
+		t.Errorf("User-Agent should be 'TestAgent', not '%s'.", userAgent)
+	}
+
+	if extraHeader != "test" {
+		t.Errorf("X-Test header should be 'test', not '%s'.", extraHeader)
+	}
+}
+
+func TestFetchWithCancelledContext(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.Retry = RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.FetchWithContext(ctx, SearchEndpoint, "/")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FetchWithContext should return context deadline error, not '%v'.", err)
+	}
+
+	if errors.Is(err, types.ErrUpstreamUnavailable) {
+		t.Errorf("Cancelled requests shouldn't be reported as upstream failures.")
+	}
+
+	if time.Since(start) > time.Second {
+		t.Errorf("FetchWithContext should stop retrying when context is done, it took %s.", time.Since(start))
+	}
+}
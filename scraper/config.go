@@ -0,0 +1,178 @@
+package scraper
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	viperLib "github.com/spf13/viper"
+)
+
+// Default politeness settings, upstream is requested at most once per second
+// allowing short bursts of two requests.
+const (
+	DefaultRequestsPerSecond = 1.0
+	DefaultBurst             = 2
+)
+
+// Supported cache types, responses are not cached by default.
+const (
+	NoCacheType     = "none"
+	MemoryCacheType = "memory"
+	DiskCacheType   = "disk"
+)
+
+const DefaultCacheSize = 1000
+
+// DefaultJobTimeout bounds how long a single job can keep scraping.
+const DefaultJobTimeout = 5 * time.Minute
+
+// Config holds the Metal Archives specific settings found under the
+// [metal_archives] section of the service config file. Every key is optional.
+// Zero values keep NewClient defaults, a negative MaxResults removes the cap.
+type Config struct {
+	BaseURL           string
+	UserAgent         string
+	RequestsPerSecond float64
+	Burst             int
+	MaxAttempts       int
+	RetryBaseDelay    time.Duration
+	RetryMaxDelay     time.Duration
+	CacheType         string
+	CacheSize         int
+	CacheDir          string
+	CacheTTL          map[Endpoint]time.Duration
+	MaxResults        int
+	MatchMode         types.MatchMode
+	JobTimeout        time.Duration
+}
+
+func ReadConfig() (Config, error) {
+	var configFileLocation string
+	var config Config
+
+	var envVariable string = "MUSIC_MANAGER_SERVICE_CONFIG_FILE_LOCATION"
+
+	viper := viperLib.New()
+
+	viper.BindEnv(envVariable)
+	configFileLocation = viper.GetString(envVariable)
+	if configFileLocation == "" {
+		configFileLocation = "/etc/music-manager/"
+	}
+	viper.SetConfigName("config")
+	viper.SetConfigType("toml")
+	viper.AddConfigPath(configFileLocation)
+
+	viper.SetDefault("metal_archives.base_url", DefaultBaseURL)
+	viper.SetDefault("metal_archives.user_agent", DefaultUserAgent)
+	viper.SetDefault("metal_archives.requests_per_second", DefaultRequestsPerSecond)
+	viper.SetDefault("metal_archives.burst", DefaultBurst)
+	viper.SetDefault("metal_archives.max_attempts", DefaultRetryPolicy.MaxAttempts)
+	viper.SetDefault("metal_archives.retry_base_delay", DefaultRetryPolicy.BaseDelay)
+	viper.SetDefault("metal_archives.retry_max_delay", DefaultRetryPolicy.MaxDelay)
+	viper.SetDefault("metal_archives.max_results", DefaultMaxResults)
+	viper.SetDefault("metal_archives.match_mode", "normalized")
+	viper.SetDefault("metal_archives.job_timeout", DefaultJobTimeout)
+	viper.SetDefault("metal_archives.cache.type", NoCacheType)
+	viper.SetDefault("metal_archives.cache.size", DefaultCacheSize)
+	for endpoint, ttl := range DefaultCacheTTL() {
+		viper.SetDefault("metal_archives.cache.ttl."+string(endpoint), ttl)
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		return config, errors.New(errors.New("Fatal error reading config file: ").Error() + err.Error())
+	}
+
+	config.BaseURL = viper.GetString("metal_archives.base_url")
+	config.UserAgent = viper.GetString("metal_archives.user_agent")
+	config.RequestsPerSecond = viper.GetFloat64("metal_archives.requests_per_second")
+	config.Burst = viper.GetInt("metal_archives.burst")
+	config.MaxAttempts = viper.GetInt("metal_archives.max_attempts")
+	config.RetryBaseDelay = viper.GetDuration("metal_archives.retry_base_delay")
+	config.RetryMaxDelay = viper.GetDuration("metal_archives.retry_max_delay")
+	config.MaxResults = viper.GetInt("metal_archives.max_results")
+	if config.MaxResults == 0 {
+		config.MaxResults = -1
+	}
+	config.JobTimeout = viper.GetDuration("metal_archives.job_timeout")
+	config.CacheType = viper.GetString("metal_archives.cache.type")
+	config.CacheSize = viper.GetInt("metal_archives.cache.size")
+	config.CacheDir = viper.GetString("metal_archives.cache.dir")
+	config.CacheTTL = make(map[Endpoint]time.Duration)
+	for endpoint := range DefaultCacheTTL() {
+		config.CacheTTL[endpoint] = viper.GetDuration("metal_archives.cache.ttl." + string(endpoint))
+	}
+
+	if config.RequestsPerSecond < 0 {
+		return config, errors.New("Fatal error config: metal_archives requests_per_second can't be negative.")
+	}
+
+	if config.JobTimeout < 0 {
+		return config, errors.New("Fatal error config: metal_archives job_timeout can't be negative.")
+	}
+
+	if config.MaxAttempts < 1 {
+		return config, errors.New("Fatal error config: metal_archives max_attempts must be at least 1.")
+	}
+
+	switch viper.GetString("metal_archives.match_mode") {
+	case "normalized":
+		config.MatchMode = types.NormalizedMatch
+	case "case_insensitive":
+		config.MatchMode = types.CaseInsensitiveMatch
+	default:
+		return config, errors.New("Fatal error config: metal_archives match_mode must be normalized or case_insensitive.")
+	}
+
+	switch config.CacheType {
+	case NoCacheType, MemoryCacheType:
+	case DiskCacheType:
+		if config.CacheDir == "" {
+			return config, errors.New("Fatal error config: metal_archives disk cache requires a dir.")
+		}
+	default:
+		return config, errors.New("Fatal error config: metal_archives cache type must be none, memory or disk.")
+	}
+
+	return config, nil
+}
+
+func NewClientFromConfig(httpClient http.Client, config Config) (Client, error) {
+	client := NewClient(httpClient)
+
+	if config.BaseURL != "" {
+		client.BaseURL = config.BaseURL
+	}
+	if config.UserAgent != "" {
+		client.UserAgent = config.UserAgent
+	}
+	if config.MaxAttempts > 0 {
+		client.Retry = RetryPolicy{MaxAttempts: config.MaxAttempts, BaseDelay: config.RetryBaseDelay, MaxDelay: config.RetryMaxDelay}
+	}
+	if config.MaxResults != 0 {
+		client.MaxResults = config.MaxResults
+	}
+	client.MatchMode = config.MatchMode
+	// A zero rate disables the limiter.
+	if config.RequestsPerSecond > 0 {
+		client.Limiter = NewRateLimiter(config.RequestsPerSecond, config.Burst)
+	}
+
+	switch config.CacheType {
+	case MemoryCacheType:
+		client.Cache = NewMemoryCache(config.CacheSize)
+	case DiskCacheType:
+		diskCache, err := NewDiskCache(config.CacheDir)
+		if err != nil {
+			return client, errors.New(errors.New("Fatal error creating cache dir: ").Error() + err.Error())
+		}
+		client.Cache = diskCache
+	}
+	for endpoint, ttl := range config.CacheTTL {
+		client.CacheTTL[endpoint] = ttl
+	}
+
+	return client, nil
+}
@@ -0,0 +1,222 @@
+// +build integration_tests unit_tests
+
+package scraper
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) {
+	configDir := t.TempDir()
+
+	err := ioutil.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("Config file couldn't be written: %s", err.Error())
+	}
+
+	os.Setenv("MUSIC_MANAGER_SERVICE_CONFIG_FILE_LOCATION", configDir)
+}
+
+func TestReadConfigNoFile(t *testing.T) {
+	os.Setenv("MUSIC_MANAGER_SERVICE_CONFIG_FILE_LOCATION", t.TempDir())
+	defer os.Unsetenv("MUSIC_MANAGER_SERVICE_CONFIG_FILE_LOCATION")
+
+	_, err := ReadConfig()
+
+	if err == nil {
+		t.Errorf("ReadConfig should fail when there is no config file.")
+	}
+}
+
+func TestReadConfigDefaults(t *testing.T) {
+	writeConfig(t, `
+[server]
+host = "localhost"
+`)
+	defer os.Unsetenv("MUSIC_MANAGER_SERVICE_CONFIG_FILE_LOCATION")
+
+	config, err := ReadConfig()
+
+	if err != nil {
+		t.Errorf("ReadConfig shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if config.BaseURL != DefaultBaseURL {
+		t.Errorf("BaseURL should be '%s', not '%s'.", DefaultBaseURL, config.BaseURL)
+	}
+
+	if config.UserAgent != DefaultUserAgent {
+		t.Errorf("UserAgent should be '%s', not '%s'.", DefaultUserAgent, config.UserAgent)
+	}
+
+	if config.RequestsPerSecond != DefaultRequestsPerSecond || config.Burst != DefaultBurst {
+		t.Errorf("Rate limit should default to %f requests per second with burst %d, not %f and %d.", DefaultRequestsPerSecond, DefaultBurst, config.RequestsPerSecond, config.Burst)
+	}
+
+	if config.MaxAttempts != DefaultRetryPolicy.MaxAttempts || config.RetryBaseDelay != DefaultRetryPolicy.BaseDelay || config.RetryMaxDelay != DefaultRetryPolicy.MaxDelay {
+		t.Errorf("Retry settings should default to DefaultRetryPolicy.")
+	}
+
+	if config.CacheType != NoCacheType {
+		t.Errorf("Cache should be disabled by default, type is '%s'.", config.CacheType)
+	}
+
+	if config.JobTimeout != DefaultJobTimeout {
+		t.Errorf("Job timeout should default to %s, not %s.", DefaultJobTimeout, config.JobTimeout)
+	}
+}
+
+func TestReadConfigMetalArchivesSection(t *testing.T) {
+	writeConfig(t, `
+[metal_archives]
+base_url = "http://localhost:8080"
+user_agent = "Staging"
+requests_per_second = 0.5
+burst = 4
+max_attempts = 5
+retry_base_delay = "1s"
+retry_max_delay = "1m"
+job_timeout = "30s"
+
+[metal_archives.cache]
+type = "memory"
+size = 10
+
+[metal_archives.cache.ttl]
+album = "48h"
+`)
+	defer os.Unsetenv("MUSIC_MANAGER_SERVICE_CONFIG_FILE_LOCATION")
+
+	config, err := ReadConfig()
+
+	if err != nil {
+		t.Errorf("ReadConfig shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if config.JobTimeout != 30*time.Second {
+		t.Errorf("Job timeout should be 30s, not %s.", config.JobTimeout)
+	}
+
+	client, err := NewClientFromConfig(http.Client{}, config)
+
+	if err != nil {
+		t.Errorf("NewClientFromConfig shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if client.BaseURL != "http://localhost:8080" {
+		t.Errorf("BaseURL should be 'http://localhost:8080', not '%s'.", client.BaseURL)
+	}
+
+	if client.UserAgent != "Staging" {
+		t.Errorf("UserAgent should be 'Staging', not '%s'.", client.UserAgent)
+	}
+
+	if client.Limiter == nil {
+		t.Fatalf("Client should have a rate limiter.")
+	}
+
+	if client.Limiter.requestsPerSecond != 0.5 || client.Limiter.burst != 4 {
+		t.Errorf("Limiter should allow 0.5 requests per second with burst 4, not %f and %d.", client.Limiter.requestsPerSecond, client.Limiter.burst)
+	}
+
+	if client.Retry.MaxAttempts != 5 || client.Retry.BaseDelay != time.Second || client.Retry.MaxDelay != time.Minute {
+		t.Errorf("Retry policy should be 5 attempts from 1s to 1m, not %d attempts from %s to %s.", client.Retry.MaxAttempts, client.Retry.BaseDelay, client.Retry.MaxDelay)
+	}
+
+	if _, isMemoryCache := client.Cache.(*MemoryCache); !isMemoryCache {
+		t.Errorf("Client should use a memory cache.")
+	}
+
+	if client.CacheTTL[AlbumEndpoint] != 48*time.Hour || client.CacheTTL[SearchEndpoint] != time.Hour {
+		t.Errorf("Album TTL should be 48h and search TTL should keep its 1h default, not %s and %s.", client.CacheTTL[AlbumEndpoint], client.CacheTTL[SearchEndpoint])
+	}
+}
+
+func TestReadConfigDiskCacheRequiresDir(t *testing.T) {
+	writeConfig(t, `
+[metal_archives.cache]
+type = "disk"
+`)
+	defer os.Unsetenv("MUSIC_MANAGER_SERVICE_CONFIG_FILE_LOCATION")
+
+	_, err := ReadConfig()
+
+	if err == nil {
+		t.Errorf("ReadConfig should fail when disk cache has no dir.")
+	}
+}
+
+func TestReadConfigNegativeRate(t *testing.T) {
+	writeConfig(t, `
+[metal_archives]
+requests_per_second = -1
+`)
+	defer os.Unsetenv("MUSIC_MANAGER_SERVICE_CONFIG_FILE_LOCATION")
+
+	_, err := ReadConfig()
+
+	if err == nil {
+		t.Errorf("ReadConfig should fail when requests_per_second is negative.")
+	}
+}
+
+func TestReadConfigNegativeJobTimeout(t *testing.T) {
+	writeConfig(t, `
+[metal_archives]
+job_timeout = "-1s"
+`)
+	defer os.Unsetenv("MUSIC_MANAGER_SERVICE_CONFIG_FILE_LOCATION")
+
+	_, err := ReadConfig()
+
+	if err == nil {
+		t.Errorf("ReadConfig should fail when job_timeout is negative.")
+	}
+}
+
+func TestNewClientFromEmptyConfig(t *testing.T) {
+	defaults := NewClient(http.Client{})
+
+	client, err := NewClientFromConfig(http.Client{}, Config{})
+
+	if err != nil {
+		t.Fatalf("NewClientFromConfig shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if client.MaxResults != defaults.MaxResults || client.MatchMode != defaults.MatchMode {
+		t.Errorf("Empty config should keep NewClient max results %d and match mode %d, not %d and %d.", defaults.MaxResults, defaults.MatchMode, client.MaxResults, client.MatchMode)
+	}
+
+	if client.BaseURL != defaults.BaseURL || client.UserAgent != defaults.UserAgent || client.Retry != defaults.Retry {
+		t.Errorf("Empty config should keep NewClient base URL, user agent and retry policy.")
+	}
+}
+
+func TestReadConfigUncappedResults(t *testing.T) {
+	writeConfig(t, `
+[metal_archives]
+max_results = 0
+`)
+	defer os.Unsetenv("MUSIC_MANAGER_SERVICE_CONFIG_FILE_LOCATION")
+
+	config, err := ReadConfig()
+
+	if err != nil {
+		t.Fatalf("ReadConfig shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	client, err := NewClientFromConfig(http.Client{}, config)
+
+	if err != nil {
+		t.Fatalf("NewClientFromConfig shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if client.MaxResults > 0 {
+		t.Errorf("Setting max_results to 0 should remove the cap, not keep %d.", client.MaxResults)
+	}
+}
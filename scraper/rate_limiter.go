@@ -0,0 +1,95 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket shared by every request made through a Client.
+type RateLimiter struct {
+	mutex             sync.Mutex
+	requestsPerSecond float64
+	burst             int
+	tokens            float64
+	last              time.Time
+	requests          int
+	lastWait          time.Duration
+	totalWait         time.Duration
+}
+
+// RateLimiterStats reports how much time requests spent waiting for a token.
+type RateLimiterStats struct {
+	Requests  int
+	LastWait  time.Duration
+	TotalWait time.Duration
+}
+
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		requestsPerSecond: requestsPerSecond,
+		burst:             burst,
+		tokens:            float64(burst),
+		last:              time.Now(),
+	}
+}
+
+// reserve takes a token from the bucket and returns how long the caller has to wait before using it.
+func (limiter *RateLimiter) reserve() time.Duration {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	var wait time.Duration
+
+	if limiter.requestsPerSecond > 0 {
+		now := time.Now()
+		limiter.tokens += now.Sub(limiter.last).Seconds() * limiter.requestsPerSecond
+		if limiter.tokens > float64(limiter.burst) {
+			limiter.tokens = float64(limiter.burst)
+		}
+		limiter.last = now
+
+		limiter.tokens--
+		if limiter.tokens < 0 {
+			wait = time.Duration(-limiter.tokens / limiter.requestsPerSecond * float64(time.Second))
+		}
+	}
+
+	limiter.requests++
+	limiter.lastWait = wait
+	limiter.totalWait += wait
+
+	return wait
+}
+
+// Wait blocks until a request is allowed and returns the time spent waiting.
+// If ctx is cancelled first the reserved token is given back.
+func (limiter *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	wait := limiter.reserve()
+	if wait <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return wait, nil
+	case <-ctx.Done():
+		limiter.mutex.Lock()
+		limiter.tokens++
+		limiter.mutex.Unlock()
+		return 0, ctx.Err()
+	}
+}
+
+func (limiter *RateLimiter) Stats() RateLimiterStats {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	return RateLimiterStats{Requests: limiter.requests, LastWait: limiter.lastWait, TotalWait: limiter.totalWait}
+}
@@ -0,0 +1,103 @@
+// +build integration_tests unit_tests
+
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurstDoesNotWait(t *testing.T) {
+	limiter := NewRateLimiter(1, 3)
+
+	for i := 0; i < 3; i++ {
+		if waited, _ := limiter.Wait(context.Background()); waited != 0 {
+			t.Errorf("Request %d is inside burst and shouldn't wait, it waited %s.", i, waited)
+		}
+	}
+
+	stats := limiter.Stats()
+	if stats.Requests != 3 {
+		t.Errorf("Limiter should have counted 3 requests, not %d.", stats.Requests)
+	}
+}
+
+func TestRateLimiterWaitsWhenBucketIsEmpty(t *testing.T) {
+	limiter := NewRateLimiter(20, 1)
+
+	limiter.Wait(context.Background())
+	start := time.Now()
+	waited, _ := limiter.Wait(context.Background())
+	elapsed := time.Since(start)
+
+	if waited < 40*time.Millisecond || waited > 50*time.Millisecond {
+		t.Errorf("Second request should wait around 50ms, it waited %s.", waited)
+	}
+
+	if elapsed < waited {
+		t.Errorf("Wait returned after %s but reported %s.", elapsed, waited)
+	}
+
+	stats := limiter.Stats()
+	if stats.LastWait != waited || stats.TotalWait != waited {
+		t.Errorf("Limiter stats should report %s waited, got last %s and total %s.", waited, stats.LastWait, stats.TotalWait)
+	}
+}
+
+func TestRateLimiterZeroRateIsUnlimited(t *testing.T) {
+	limiter := NewRateLimiter(0, 1)
+
+	for i := 0; i < 10; i++ {
+		if waited, _ := limiter.Wait(context.Background()); waited != 0 {
+			t.Errorf("Unlimited limiter shouldn't wait, it waited %s.", waited)
+		}
+	}
+}
+
+func TestFetchReportsWaitedTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.Limiter = NewRateLimiter(20, 1)
+
+	first, _ := client.Fetch(SearchEndpoint, "/")
+	second, err := client.Fetch(SearchEndpoint, "/")
+
+	if err != nil {
+		t.Errorf("Fetch shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if first.Waited != 0 {
+		t.Errorf("First request shouldn't wait, it waited %s.", first.Waited)
+	}
+
+	if second.Waited == 0 {
+		t.Errorf("Second request should have waited for the limiter.")
+	}
+}
+
+func TestRateLimiterWaitIsCancelled(t *testing.T) {
+	limiter := NewRateLimiter(0.1, 1)
+	limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := limiter.Wait(ctx)
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("Wait should return context deadline error, not '%v'.", err)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Errorf("Wait should stop when context is done, it took %s.", time.Since(start))
+	}
+}
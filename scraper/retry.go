@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy sets how many times a request is attempted and how long to wait between attempts.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// RetryError is returned when a request keeps failing after every allowed attempt.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	if e.Attempts == 1 {
+		return fmt.Sprintf("%s (1 attempt made)", e.Err.Error())
+	}
+	return fmt.Sprintf("%s (%d attempts made)", e.Err.Error(), e.Attempts)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+func retryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns an exponential delay for the given attempt with full jitter.
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	delay := policy.BaseDelay
+	// MaxDelay 0 means no cap, doubling only stops before overflowing.
+	for i := 1; i < attempt && delay < math.MaxInt64/2; i++ {
+		if policy.MaxDelay > 0 && delay >= policy.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay))) + 1
+}
+
+// retryAfter reads Retry-After header, which may contain seconds or an HTTP date.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
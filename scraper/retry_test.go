@@ -0,0 +1,230 @@
+// +build integration_tests unit_tests
+
+package scraper
+
+import (
+	"errors"
+	types "github.com/a-castellano/music-manager-metal-archives-wrapper/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackoffIsBoundedByMaxDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 40 * time.Millisecond}
+
+	for attempt := 1; attempt < 10; attempt++ {
+		delay := policy.backoff(attempt)
+		if delay <= 0 || delay > 40*time.Millisecond {
+			t.Errorf("Backoff for attempt %d should be between 0 and 40ms, not %s.", attempt, delay)
+		}
+	}
+}
+
+func TestBackoffWithoutMaxDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond}
+
+	longest := time.Duration(0)
+	for i := 0; i < 100; i++ {
+		if delay := policy.backoff(5); delay > longest {
+			longest = delay
+		}
+	}
+
+	if longest <= 10*time.Millisecond || longest > 160*time.Millisecond {
+		t.Errorf("Backoff for attempt 5 without MaxDelay should grow up to 160ms, longest was %s.", longest)
+	}
+
+	if delay := policy.backoff(200); delay <= 0 {
+		t.Errorf("Backoff for attempt 200 without MaxDelay shouldn't overflow, it was %s.", delay)
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "7")
+
+	delay, found := retryAfter(header, time.Now())
+
+	if !found || delay != 7*time.Second {
+		t.Errorf("Retry-After '7' should be 7s, not %s.", delay)
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	now := time.Date(2021, time.June, 15, 10, 0, 0, 0, time.UTC)
+	header := http.Header{}
+	header.Set("Retry-After", now.Add(30*time.Second).Format(http.TimeFormat))
+
+	delay, found := retryAfter(header, now)
+
+	if !found || delay != 30*time.Second {
+		t.Errorf("Retry-After date should be 30s away, not %s.", delay)
+	}
+}
+
+func TestRetryAfterMissing(t *testing.T) {
+	if _, found := retryAfter(http.Header{}, time.Now()); found {
+		t.Errorf("Retry-After shouldn't be found in empty headers.")
+	}
+}
+
+func TestFetchRetriesUnavailableUpstream(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	response, err := client.Fetch(SearchEndpoint, "/")
+
+	if err != nil {
+		t.Errorf("Fetch shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if response.Attempts != 3 || string(response.Body) != "body" {
+		t.Errorf("Fetch should succeed on third attempt, it made %d attempts and got '%s'.", response.Attempts, string(response.Body))
+	}
+}
+
+func TestFetchHonorsRetryAfter(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.Retry = RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second}
+
+	start := time.Now()
+	_, err := client.Fetch(SearchEndpoint, "/")
+
+	if err != nil {
+		t.Errorf("Fetch shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if time.Since(start) < time.Second {
+		t.Errorf("Fetch should have waited Retry-After second, it waited %s.", time.Since(start))
+	}
+}
+
+func TestFetchRetryAfterOverMaxDelay(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second}
+
+	start := time.Now()
+	_, err := client.Fetch(SearchEndpoint, "/")
+
+	if time.Since(start) > time.Second {
+		t.Errorf("Fetch shouldn't wait for Retry-After longer than MaxDelay, it waited %s.", time.Since(start))
+	}
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 1 {
+		t.Fatalf("Fetch should return a RetryError after 1 attempt, not '%v'.", err)
+	}
+
+	if !errors.Is(err, types.ErrRateLimited) {
+		t.Errorf("Error should be ErrRateLimited, not '%s'.", err.Error())
+	}
+
+	if requests != 1 {
+		t.Errorf("Upstream should have received 1 request, not %d.", requests)
+	}
+}
+
+func TestFetchRetryAfterOverMaxDelayUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second}
+
+	_, err := client.Fetch(SearchEndpoint, "/")
+
+	if !errors.Is(err, types.ErrUpstreamUnavailable) || errors.Is(err, types.ErrRateLimited) {
+		t.Errorf("Long Retry-After on a 503 should keep reporting ErrUpstreamUnavailable, not '%v'.", err)
+	}
+}
+
+func TestFetchGivesUpAfterMaxAttempts(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+	client.Retry = RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	_, err := client.Fetch(SearchEndpoint, "/")
+
+	if err == nil {
+		t.Fatalf("Fetch should fail when upstream keeps failing.")
+	}
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 4 {
+		t.Errorf("Fetch should return a RetryError after 4 attempts, got '%s'.", err.Error())
+	}
+
+	if !strings.HasSuffix(err.Error(), "(4 attempts made)") {
+		t.Errorf("Error should say how many attempts were made, got '%s'.", err.Error())
+	}
+
+	if requests != 4 {
+		t.Errorf("Upstream should have received 4 requests, not %d.", requests)
+	}
+}
+
+func TestFetchDoesNotRetryNotFound(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(http.Client{})
+	client.BaseURL = server.URL
+
+	response, _ := client.Fetch(SearchEndpoint, "/")
+
+	if requests != 1 || response.Attempts != 1 {
+		t.Errorf("Not found responses shouldn't be retried, upstream received %d requests.", requests)
+	}
+}
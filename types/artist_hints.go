@@ -0,0 +1,43 @@
+package types
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// ArtistHints help choosing between bands sharing a name. Job Manager sends
+// them gob encoded as InfoRetrieval Data of ArtistName retrievals.
+type ArtistHints struct {
+	Country string
+	Genres  []string
+	Album   string
+	Year    int
+}
+
+// Empty tells whether no hint is set.
+func (hints ArtistHints) Empty() bool {
+	return hints.Country == "" && len(hints.Genres) == 0 && hints.Album == "" && hints.Year == 0
+}
+
+func EncodeArtistHints(hints ArtistHints) ([]byte, error) {
+	var encodedHints []byte
+	var network bytes.Buffer
+	enc := gob.NewEncoder(&network)
+	err := enc.Encode(hints)
+	if err != nil {
+		return encodedHints, err
+	}
+	encodedHints = network.Bytes()
+	return encodedHints, nil
+}
+
+func DecodeArtistHints(encoded []byte) (ArtistHints, error) {
+	var hints ArtistHints
+	network := bytes.NewBuffer(encoded)
+	dec := gob.NewDecoder(network)
+	err := dec.Decode(&hints)
+	if err != nil {
+		return hints, err
+	}
+	return hints, nil
+}
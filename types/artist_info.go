@@ -0,0 +1,53 @@
+package types
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	commontypes "github.com/a-castellano/music-manager-common-types/types"
+)
+
+// Artist extends commontypes.Artist with data only this service knows about.
+// Gob ignores fields missing in the receiving type, so payloads encoded from
+// it are still decoded by commontypes.DecodeArtist and commontypes.DecodeArtistInfo.
+type Artist struct {
+	Name    string
+	URL     string
+	ID      string
+	Genre   string
+	Country string
+	Records []commontypes.Record
+	Aliases []string
+}
+
+// ArtistInfo Confidence and MatchedHints are only set when ArtistHints were
+// used to choose Data among bands sharing its name.
+type ArtistInfo struct {
+	Data         Artist
+	ExtraData    []Artist
+	Confidence   float64
+	MatchedHints []string
+}
+
+func EncodeArtistInfo(artists ArtistInfo) ([]byte, error) {
+	var encodedArtistInfo []byte
+	var network bytes.Buffer
+	enc := gob.NewEncoder(&network)
+	err := enc.Encode(artists)
+	if err != nil {
+		return encodedArtistInfo, err
+	}
+	encodedArtistInfo = network.Bytes()
+	return encodedArtistInfo, nil
+}
+
+func DecodeArtistInfo(encoded []byte) (ArtistInfo, error) {
+	var artistinfo ArtistInfo
+	network := bytes.NewBuffer(encoded)
+	dec := gob.NewDecoder(network)
+	err := dec.Decode(&artistinfo)
+	if err != nil {
+		return artistinfo, err
+	}
+	return artistinfo, nil
+}
@@ -0,0 +1,39 @@
+// +build integration_tests unit_tests
+
+package types
+
+import (
+	"testing"
+
+	commontypes "github.com/a-castellano/music-manager-common-types/types"
+)
+
+func TestArtistInfoIsReadableAsCommonArtistInfo(t *testing.T) {
+	artistInfo := ArtistInfo{
+		Data:      Artist{Name: "Krimparturr", ID: "21151", Aliases: []string{"Krimpartûrr Bürzum Shi-Hai"}},
+		ExtraData: []Artist{{Name: "Burzum", ID: "88"}},
+	}
+
+	encoded, err := EncodeArtistInfo(artistInfo)
+	if err != nil {
+		t.Fatalf("EncodeArtistInfo shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	commonArtistInfo, err := commontypes.DecodeArtistInfo(encoded)
+	if err != nil {
+		t.Fatalf("commontypes.DecodeArtistInfo shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if commonArtistInfo.Data.Name != "Krimparturr" || commonArtistInfo.ExtraData[0].ID != "88" {
+		t.Errorf("commontypes.DecodeArtistInfo should read artist fields.")
+	}
+
+	decoded, err := DecodeArtistInfo(encoded)
+	if err != nil {
+		t.Fatalf("DecodeArtistInfo shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if len(decoded.Data.Aliases) != 1 || decoded.Data.Aliases[0] != "Krimpartûrr Bürzum Shi-Hai" {
+		t.Errorf("DecodeArtistInfo should read artist aliases.")
+	}
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+)
+
+// BandLink is a band link found in upstream listing cells.
+type BandLink struct {
+	Name string
+	URL  string
+	ID   string
+}
+
+var (
+	linkre   = regexp.MustCompile(`<a href=['"]([^'"]+)['"][^>]*>([^<]+)</a>`)
+	bandIDre = regexp.MustCompile(`/bands/[^/]+/([0-9]+)/?(?:[?#].*)?$`)
+)
+
+// BandID returns the band ID of a band page URL.
+func BandID(url string) (string, bool) {
+	match := bandIDre.FindStringSubmatch(url)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// ReadBandLink reads the first link of cell, which must point to a band page.
+func ReadBandLink(cell string) (BandLink, error) {
+	var link BandLink
+
+	match := linkre.FindStringSubmatch(cell)
+	if match == nil {
+		return link, fmt.Errorf("Cell has no band link.")
+	}
+	link.URL = match[1]
+	link.Name = html.UnescapeString(match[2])
+
+	id, found := BandID(link.URL)
+	if !found {
+		return link, fmt.Errorf("Band URL '%s' has no ID.", link.URL)
+	}
+	link.ID = id
+
+	return link, nil
+}
+
+// ReadBandLinks reads every band link of cell, splits list several bands.
+func ReadBandLinks(cell string) []BandLink {
+	var links []BandLink
+
+	for _, match := range linkre.FindAllStringSubmatch(cell, -1) {
+		if id, found := BandID(match[1]); found {
+			links = append(links, BandLink{Name: html.UnescapeString(match[2]), URL: match[1], ID: id})
+		}
+	}
+
+	return links
+}
@@ -0,0 +1,52 @@
+// +build integration_tests unit_tests
+
+package types
+
+import (
+	"testing"
+)
+
+func TestBandID(t *testing.T) {
+	for url, expected := range map[string]string{
+		"https://www.metal-archives.com/bands/Burzum/88":        "88",
+		"https://www.metal-archives.com/bands/Burzum/88/":       "88",
+		"https://www.metal-archives.com/bands/Burzum/88?tab=1":  "88",
+		"https://www.metal-archives.com/bands/B%C3%B6lzer/3540": "3540",
+	} {
+		if id, found := BandID(url); !found || id != expected {
+			t.Errorf("'%s' band ID should be '%s', not '%s'.", url, expected, id)
+		}
+	}
+
+	if _, found := BandID("https://www.metal-archives.com/artists/Varg_Vikernes/2"); found {
+		t.Errorf("Musician URLs shouldn't have a band ID.")
+	}
+}
+
+func TestReadBandLink(t *testing.T) {
+	link, err := ReadBandLink(`<a href="https://www.metal-archives.com/bands/Simon_%26_Garfunkel/1234" title="Simon &amp; Garfunkel (US)">Simon &amp; Garfunkel</a>  <!-- 1.0 -->`)
+
+	if err != nil {
+		t.Fatalf("ReadBandLink shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if link.Name != "Simon & Garfunkel" || link.ID != "1234" || link.URL != "https://www.metal-archives.com/bands/Simon_%26_Garfunkel/1234" {
+		t.Errorf("Band link is wrong, found '%v'.", link)
+	}
+
+	if _, err := ReadBandLink("Simon & Garfunkel"); err == nil {
+		t.Errorf("ReadBandLink should fail on cells without links.")
+	}
+
+	if _, err := ReadBandLink(`<a href="https://www.metal-archives.com/labels/Deathlike_Silence/1">Deathlike Silence</a>`); err == nil {
+		t.Errorf("ReadBandLink should fail on links which are not band pages.")
+	}
+}
+
+func TestReadBandLinks(t *testing.T) {
+	links := ReadBandLinks(`<a href='https://www.metal-archives.com/bands/Mayhem/67'>Mayhem</a> / <a href="https://www.metal-archives.com/labels/Misanthropy/2">Misanthropy</a> / <a href="https://www.metal-archives.com/bands/Burzum/88">Burzum</a>`)
+
+	if len(links) != 2 || links[0].ID != "67" || links[1].Name != "Burzum" {
+		t.Errorf("Only band links should be read, found '%v'.", links)
+	}
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"time"
+)
+
+// ChangeTimeLayout is the layout of upstream addition and update timestamps.
+const ChangeTimeLayout = "2006-01-02 15:04:05"
+
+// Change is an addition or update listed by upstream latest listings. Date
+// is read as UTC, upstream does not tell its time zone.
+type Change struct {
+	Date    time.Time
+	User    string
+	UserURL string
+}
+
+var changeTimere = regexp.MustCompile(`[0-9]{4}-[0-9]{2}-[0-9]{2} [0-9]{2}:[0-9]{2}:[0-9]{2}`)
+
+// ChangeMonth returns the month selection latest listings are requested by.
+func ChangeMonth(month time.Time) string {
+	return month.Format("2006-01")
+}
+
+// ReadChange reads the timestamp and user cells of latest listings rows.
+// Changes made by removed users have no user link, only their name.
+func ReadChange(timeCell string, userCell string) (Change, error) {
+	var change Change
+
+	match := changeTimere.FindString(timeCell)
+	if match == "" {
+		return change, fmt.Errorf("Change time '%s' has no timestamp.", timeCell)
+	}
+	date, err := time.Parse(ChangeTimeLayout, match)
+	if err != nil {
+		return change, err
+	}
+	change.Date = date
+
+	if link := linkre.FindStringSubmatch(userCell); link != nil {
+		change.UserURL = link[1]
+		change.User = html.UnescapeString(link[2])
+	} else {
+		change.User = CellText(userCell)
+	}
+
+	return change, nil
+}
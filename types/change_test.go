@@ -0,0 +1,48 @@
+// +build integration_tests unit_tests
+
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadChange(t *testing.T) {
+	change, err := ReadChange("2024-05-31 21:47:23", `<a href="https://www.metal-archives.com/users/Dr%C3%A1gon" class="profileMenu">Drágon</a>`)
+
+	if err != nil {
+		t.Fatalf("ReadChange shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if !change.Date.Equal(time.Date(2024, time.May, 31, 21, 47, 23, 0, time.UTC)) {
+		t.Errorf("Change date should be 2024-05-31 21:47:23, not '%s'.", change.Date)
+	}
+
+	if change.User != "Drágon" || change.UserURL != "https://www.metal-archives.com/users/Dr%C3%A1gon" {
+		t.Errorf("Change user should be 'Drágon' with its profile, not '%s' '%s'.", change.User, change.UserURL)
+	}
+}
+
+func TestReadChangeRemovedUser(t *testing.T) {
+	change, err := ReadChange("2024-05-31 21:47:23", "<span>Deleted &amp; gone</span>")
+
+	if err != nil {
+		t.Fatalf("ReadChange shouldn't fail, error was '%s'.", err.Error())
+	}
+
+	if change.User != "Deleted & gone" || change.UserURL != "" {
+		t.Errorf("Removed users should only have a name, not '%s' '%s'.", change.User, change.UserURL)
+	}
+}
+
+func TestReadChangeWithoutTimestamp(t *testing.T) {
+	if _, err := ReadChange("May 31st", "someone"); err == nil {
+		t.Errorf("ReadChange should fail without a timestamp.")
+	}
+}
+
+func TestChangeMonth(t *testing.T) {
+	if ChangeMonth(time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)) != "2024-03" {
+		t.Errorf("March 2024 should be selected as '2024-03', not '%s'.", ChangeMonth(time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)))
+	}
+}
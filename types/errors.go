@@ -0,0 +1,120 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors shared by every scraper, check them with errors.Is.
+var (
+	ErrNotFound            = errors.New("Requested resource was not found.")
+	ErrRateLimited         = errors.New("Metal Archives is rate limiting requests.")
+	ErrUpstreamUnavailable = errors.New("Metal Archives is unavailable.")
+	ErrParse               = errors.New("Metal Archives response could not be parsed.")
+	ErrNoMatch             = errors.New("No match was found.")
+	ErrBlocked             = errors.New("Metal Archives is blocking requests.")
+)
+
+// StatusError is returned when upstream answers with a non successful status code.
+type StatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Metal Archives returned status %d for %s.", e.StatusCode, e.URL)
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUpstreamUnavailable:
+		return e.StatusCode >= 500
+	default:
+		return false
+	}
+}
+
+// UnavailableError wraps network errors raised while reaching upstream.
+type UnavailableError struct {
+	Err error
+}
+
+func (e *UnavailableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UnavailableError) Is(target error) bool {
+	return target == ErrUpstreamUnavailable
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError is returned when an upstream response does not have the expected shape.
+type ParseError struct {
+	What string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ParseError) Is(target error) bool {
+	return target == ErrParse
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// BlockedPage tells which kind of page upstream served instead of the requested one.
+type BlockedPage string
+
+const (
+	ChallengePage   BlockedPage = "anti-bot challenge"
+	CaptchaPage     BlockedPage = "captcha"
+	MaintenancePage BlockedPage = "maintenance"
+)
+
+// BlockedError is returned when upstream answers with a challenge, captcha or
+// maintenance page, meaning requested data is not missing but unreachable.
+type BlockedError struct {
+	Page       BlockedPage
+	StatusCode int
+	URL        string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("Metal Archives served a %s page with status %d for %s.", e.Page, e.StatusCode, e.URL)
+}
+
+func (e *BlockedError) Is(target error) bool {
+	switch target {
+	case ErrBlocked:
+		return true
+	case ErrUpstreamUnavailable:
+		return e.Page == MaintenancePage
+	default:
+		return false
+	}
+}
+
+// NoMatchError is returned when a search succeeds but nothing matches the query.
+type NoMatchError struct {
+	Message string
+}
+
+func (e *NoMatchError) Error() string {
+	return e.Message
+}
+
+func (e *NoMatchError) Is(target error) bool {
+	return target == ErrNoMatch
+}
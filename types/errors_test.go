@@ -0,0 +1,50 @@
+// +build integration_tests unit_tests
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestStatusErrorIs(t *testing.T) {
+	if !errors.Is(&StatusError{StatusCode: 404}, ErrNotFound) {
+		t.Errorf("Status 404 should be ErrNotFound.")
+	}
+	if !errors.Is(&StatusError{StatusCode: 429}, ErrRateLimited) {
+		t.Errorf("Status 429 should be ErrRateLimited.")
+	}
+	if !errors.Is(&StatusError{StatusCode: 503}, ErrUpstreamUnavailable) {
+		t.Errorf("Status 503 should be ErrUpstreamUnavailable.")
+	}
+	if errors.Is(&StatusError{StatusCode: 403}, ErrNotFound) || errors.Is(&StatusError{StatusCode: 403}, ErrUpstreamUnavailable) {
+		t.Errorf("Status 403 should be neither ErrNotFound nor ErrUpstreamUnavailable.")
+	}
+}
+
+func TestWrappedErrorsAreFound(t *testing.T) {
+	parseErr := fmt.Errorf("Artist retrieval failed: %w", &ParseError{What: "artist search", Err: errors.New("invalid character")})
+
+	if !errors.Is(parseErr, ErrParse) {
+		t.Errorf("Wrapped ParseError should be ErrParse.")
+	}
+
+	var typedParseErr *ParseError
+	if !errors.As(parseErr, &typedParseErr) || typedParseErr.What != "artist search" {
+		t.Errorf("Wrapped ParseError should be retrievable with errors.As.")
+	}
+
+	if parseErr.Error() != "Artist retrieval failed: invalid character" {
+		t.Errorf("ParseError should keep underlying message, got '%s'.", parseErr.Error())
+	}
+
+	noMatchErr := fmt.Errorf("Artist retrieval failed: %w", &NoMatchError{Message: "No artist was found."})
+	if !errors.Is(noMatchErr, ErrNoMatch) {
+		t.Errorf("Wrapped NoMatchError should be ErrNoMatch.")
+	}
+
+	if !errors.Is(&UnavailableError{Err: errors.New("connection refused")}, ErrUpstreamUnavailable) {
+		t.Errorf("UnavailableError should be ErrUpstreamUnavailable.")
+	}
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Attribute returns the value of attribute key in node n, or an empty string.
+func Attribute(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// HasClass tells if node n has class among its classes.
+func HasClass(n *html.Node, class string) bool {
+	for _, nodeClass := range strings.Fields(Attribute(n, "class")) {
+		if nodeClass == class {
+			return true
+		}
+	}
+	return false
+}
+
+// ChildElements returns n direct children with the given tag.
+func ChildElements(n *html.Node, tag string) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == tag {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+// FindElements returns every node below n, n included, that is a tag element matching match.
+func FindElements(n *html.Node, tag string, match func(*html.Node) bool) []*html.Node {
+	var found []*html.Node
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == tag && (match == nil || match(n)) {
+			found = append(found, n)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(n)
+	return found
+}
+
+// FindElement returns the first node found by FindElements, or nil.
+func FindElement(n *html.Node, tag string, match func(*html.Node) bool) *html.Node {
+	found := FindElements(n, tag, match)
+	if len(found) == 0 {
+		return nil
+	}
+	return found[0]
+}
+
+// WithID matches elements whose id is id.
+func WithID(id string) func(*html.Node) bool {
+	return func(n *html.Node) bool {
+		return Attribute(n, "id") == id
+	}
+}
+
+// WithClass matches elements having class among their classes.
+func WithClass(class string) func(*html.Node) bool {
+	return func(n *html.Node) bool {
+		return HasClass(n, class)
+	}
+}
+
+// Definitions maps the text of every dt element below n, without its
+// trailing colon, to the dd element following it.
+func Definitions(n *html.Node) map[string]*html.Node {
+	definitions := make(map[string]*html.Node)
+	for _, term := range FindElements(n, "dt", nil) {
+		definition := term.NextSibling
+		for definition != nil && (definition.Type != html.ElementNode || definition.Data != "dd") {
+			definition = definition.NextSibling
+		}
+		if definition != nil {
+			definitions[strings.TrimSuffix(Text(term), ":")] = definition
+		}
+	}
+	return definitions
+}
+
+// Text returns the text found below n with spaces collapsed.
+func Text(n *html.Node) string {
+	var text strings.Builder
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(n)
+	return strings.Join(strings.Fields(text.String()), " ")
+}
+
+var cellTagre = regexp.MustCompile(`<!--.*?-->|<[^>]*>`)
+
+// CellText returns the text of a DataTables cell holding HTML, without tags
+// or comments and with spaces collapsed.
+func CellText(cell string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(cellTagre.ReplaceAllString(cell, " "))), " ")
+}
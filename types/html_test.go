@@ -0,0 +1,75 @@
+// +build integration_tests unit_tests
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestHTMLHelpers(t *testing.T) {
+	doc, _ := html.Parse(strings.NewReader(`
+<div id="band_stats">
+	<dl class="float_left stats">
+		<dt>Country of origin:</dt>
+		<dd><a href="https://www.metal-archives.com/lists/NO">Norway</a></dd>
+		<dt>Status:</dt>
+		<dd class="active">
+			Active
+		</dd>
+	</dl>
+</div>`))
+
+	stats := FindElement(doc, "div", WithID("band_stats"))
+	if stats == nil {
+		t.Fatalf("band_stats div should be found.")
+	}
+
+	dl := FindElement(stats, "dl", WithClass("stats"))
+	if dl == nil || !HasClass(dl, "float_left") {
+		t.Fatalf("dl with stats and float_left classes should be found.")
+	}
+
+	definitions := ChildElements(dl, "dd")
+	if len(definitions) != 2 {
+		t.Fatalf("dl should have 2 dd children, not %d.", len(definitions))
+	}
+
+	if Text(definitions[1]) != "Active" {
+		t.Errorf("Second dd text should be 'Active', not '%s'.", Text(definitions[1]))
+	}
+
+	link := FindElement(definitions[0], "a", nil)
+	if Attribute(link, "href") != "https://www.metal-archives.com/lists/NO" {
+		t.Errorf("Link href should be 'https://www.metal-archives.com/lists/NO', not '%s'.", Attribute(link, "href"))
+	}
+
+	if Attribute(link, "title") != "" {
+		t.Errorf("Missing attributes should be empty.")
+	}
+
+	if FindElement(doc, "table", nil) != nil {
+		t.Errorf("There is no table in document.")
+	}
+
+	terms := Definitions(stats)
+	if len(terms) != 2 || Text(terms["Status"]) != "Active" || terms["Country of origin"] != definitions[0] {
+		t.Errorf("Definitions should map 'Country of origin' and 'Status' to their dd elements, not '%v'.", terms)
+	}
+}
+
+func TestCellText(t *testing.T) {
+	if text := CellText(`<span class="split_up">Split-up</span>`); text != "Split-up" {
+		t.Errorf("Tags should be removed, found '%s'.", text)
+	}
+
+	if text := CellText("September 17th, 1996 <!-- 1996-09-17 -->"); text != "September 17th, 1996" {
+		t.Errorf("Comments should be removed, found '%s'.", text)
+	}
+
+	if text := CellText("  Black&nbsp;/ Thrash &amp;\n Roll "); text != "Black / Thrash & Roll" {
+		t.Errorf("Entities should be unescaped and spaces collapsed, found '%s'.", text)
+	}
+}
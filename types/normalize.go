@@ -0,0 +1,135 @@
+package types
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MatchMode sets how searched names are compared with the ones found upstream.
+type MatchMode int
+
+const (
+	// NormalizedMatch, the default, ignores letter case, diacritics,
+	// ligatures, punctuation, "&" against "and" and a leading "The".
+	NormalizedMatch MatchMode = iota
+	// CaseInsensitiveMatch only ignores letter case.
+	CaseInsensitiveMatch
+)
+
+// Match ranks, lower ranks are better matches.
+const (
+	ExactMatchRank = iota
+	CaseInsensitiveMatchRank
+	NormalizedMatchRank
+	AliasMatchRank
+	NoMatchRank
+)
+
+// Letters without Unicode decomposition.
+var letterFolding = strings.NewReplacer(
+	"æ", "ae",
+	"œ", "oe",
+	"ß", "ss",
+	"ø", "o",
+	"đ", "d",
+	"ð", "d",
+	"þ", "th",
+	"ł", "l",
+	"ı", "i",
+	"&", " and ",
+	"+", " and ",
+)
+
+// NormalizeName folds name to the key used by NormalizedMatch, for example
+// "The Mötörhead & Co." and "motorhead and co" share "motorheadandco".
+func NormalizeName(name string) string {
+	decomposed, _, err := transform.String(transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn))), name)
+	if err != nil {
+		decomposed = name
+	}
+
+	folded := letterFolding.Replace(strings.ToLower(decomposed))
+
+	words := strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+
+	var key strings.Builder
+	for i, word := range words {
+		// Leading "The" is dropped unless it is the whole name.
+		if i == 0 && word == "the" && len(words) > 1 {
+			continue
+		}
+		key.WriteString(strings.Replace(word, "'", "", -1))
+	}
+
+	return key.String()
+}
+
+// MatchRank tells how well name found upstream matches query using mode.
+func MatchRank(query string, name string, mode MatchMode) int {
+	switch {
+	case query == name:
+		return ExactMatchRank
+	case strings.ToLower(query) == strings.ToLower(name):
+		return CaseInsensitiveMatchRank
+	case mode == NormalizedMatch && NormalizeName(query) != "" && NormalizeName(query) == NormalizeName(name):
+		return NormalizedMatchRank
+	default:
+		return NoMatchRank
+	}
+}
+
+// Similarity scores how close name is to query from 0 to 1, it is the edit
+// distance between their NormalizedMatch keys relative to the longest one.
+func Similarity(query string, name string) float64 {
+	queryKey := []rune(NormalizeName(query))
+	nameKey := []rune(NormalizeName(name))
+
+	longest := len(queryKey)
+	if len(nameKey) > longest {
+		longest = len(nameKey)
+	}
+	if longest == 0 {
+		return 0
+	}
+
+	return 1 - float64(editDistance(queryKey, nameKey))/float64(longest)
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	smallest := values[0]
+	for _, value := range values[1:] {
+		if value < smallest {
+			smallest = value
+		}
+	}
+	return smallest
+}
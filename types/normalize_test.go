@@ -0,0 +1,63 @@
+// +build integration_tests unit_tests
+
+package types
+
+import (
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	names := map[string]string{
+		"Bölzer":                     "bolzer",
+		"Motörhead":                  "motorhead",
+		"Mötley Crüe":                "motleycrue",
+		"Ænima":                      "aenima",
+		"Sigur Rós":                  "sigurros",
+		"Dødheimsgard":               "dodheimsgard",
+		"Guns N' Roses":              "gunsnroses",
+		"Emerson, Lake & Palmer":     "emersonlakeandpalmer",
+		"The Black Dahlia Murder":    "blackdahliamurder",
+		"The":                        "the",
+		"Krimpartûrr Bürzum Shi-Hai": "krimparturrburzumshihai",
+		"ﬁnal":                       "final",
+	}
+
+	for name, expected := range names {
+		if NormalizeName(name) != expected {
+			t.Errorf("'%s' should be normalized to '%s', not '%s'.", name, expected, NormalizeName(name))
+		}
+	}
+}
+
+func TestMatchRank(t *testing.T) {
+	if MatchRank("Bölzer", "Bölzer", NormalizedMatch) != ExactMatchRank {
+		t.Errorf("Equal names should be an exact match.")
+	}
+	if MatchRank("burzum", "Burzum", NormalizedMatch) != CaseInsensitiveMatchRank {
+		t.Errorf("Names differing in case should be a case insensitive match.")
+	}
+	if MatchRank("Bolzer", "Bölzer", NormalizedMatch) != NormalizedMatchRank {
+		t.Errorf("Names differing in diacritics should be a normalized match.")
+	}
+	if MatchRank("Bolzer", "Bölzer", CaseInsensitiveMatch) != NoMatchRank {
+		t.Errorf("Names differing in diacritics shouldn't match when mode is CaseInsensitiveMatch.")
+	}
+	if MatchRank("Burzum", "Down to Burzum", NormalizedMatch) != NoMatchRank {
+		t.Errorf("Different names shouldn't match.")
+	}
+	if MatchRank("!!!", "???", NormalizedMatch) != NoMatchRank {
+		t.Errorf("Names made only of punctuation shouldn't match each other.")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if Similarity("Bolzer", "Bölzer") != 1 {
+		t.Errorf("Names sharing normalized key should have similarity 1, not %f.", Similarity("Bolzer", "Bölzer"))
+	}
+	if Similarity("Burzun", "Burzum") <= Similarity("Burzun", "Down to Burzum") {
+		t.Errorf("'Burzum' should be more similar to 'Burzun' than 'Down to Burzum'.")
+	}
+	if Similarity("", "") != 0 {
+		t.Errorf("Empty names should have similarity 0.")
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"regexp"
+	"strconv"
+
 	commontypes "github.com/a-castellano/music-manager-common-types/types"
 )
 
@@ -12,6 +15,19 @@ type SearchAjaxData struct {
 	Data                [][]string `json:"aaData"`
 }
 
+var scoreRe = regexp.MustCompile(`<!--\s*([0-9]+(?:\.[0-9]+)?)\s*-->`)
+
+// ReadScore returns the relevance score upstream leaves as a comment in
+// search result cells, 0 when there is none.
+func ReadScore(cell string) float64 {
+	match := scoreRe.FindStringSubmatch(cell)
+	if match == nil {
+		return 0
+	}
+	score, _ := strconv.ParseFloat(match[1], 64)
+	return score
+}
+
 func SelectRecordType(record string) commontypes.RecordType {
 	var typeFound commontypes.RecordType
 
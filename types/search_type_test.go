@@ -40,3 +40,12 @@ func TestSelectRecordType(t *testing.T) {
 		t.Errorf("'Other' string should be type 'Other'.")
 	}
 }
+
+func TestReadScore(t *testing.T) {
+	if ReadScore(`<a href="https://www.metal-archives.com/bands/Burzum/88">Burzum</a>  <!-- 11.432714 -->`) != 11.432714 {
+		t.Errorf("Score should be 11.432714, not %f.", ReadScore(`<a href="https://www.metal-archives.com/bands/Burzum/88">Burzum</a>  <!-- 11.432714 -->`))
+	}
+	if ReadScore(`<a href="https://www.metal-archives.com/bands/Burzum/88">Burzum</a>`) != 0 {
+		t.Errorf("Cells without score should have score 0.")
+	}
+}